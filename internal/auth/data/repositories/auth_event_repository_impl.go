@@ -0,0 +1,133 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"musicfy/internal/db"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// AuthEventRepositoryImpl implements the AuthEventRepository interface for PostgreSQL
+type AuthEventRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewAuthEventRepository creates a new PostgreSQL authentication event repository
+func NewAuthEventRepository() repositories.AuthEventRepository {
+	return &AuthEventRepositoryImpl{
+		db: db.GetDB(),
+	}
+}
+
+// authEventColumns lists the event columns in the order scanAuthEvent expects them
+const authEventColumns = `id, user_id, event_type, ip_address, user_agent, metadata, created_at`
+
+// Create inserts a new event into the database
+func (r *AuthEventRepositoryImpl) Create(event *entities.AuthEvent) error {
+	metadata, err := json.Marshal(event.Metadata)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO auth_events (` + authEventColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = r.db.Exec(
+		query,
+		event.ID,
+		event.UserID,
+		string(event.Type),
+		event.IPAddress,
+		event.UserAgent,
+		metadata,
+		event.CreatedAt,
+	)
+
+	return err
+}
+
+// Find lists the events matching a filter, newest first
+func (r *AuthEventRepositoryImpl) Find(filter repositories.AuthEventFilter) ([]*entities.AuthEvent, error) {
+	// Build conditions from the filter
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.UserID != nil {
+		addCondition("user_id = $%d", *filter.UserID)
+	}
+	if filter.Type != "" {
+		addCondition("event_type = $%d", string(filter.Type))
+	}
+	if !filter.From.IsZero() {
+		addCondition("created_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCondition("created_at < $%d", filter.To)
+	}
+
+	query := `SELECT ` + authEventColumns + ` FROM auth_events`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(` ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*entities.AuthEvent{}
+	for rows.Next() {
+		event, err := r.scanAuthEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// scanAuthEvent scans an event row
+func (r *AuthEventRepositoryImpl) scanAuthEvent(row interface{ Scan(dest ...any) error }) (*entities.AuthEvent, error) {
+	var event entities.AuthEvent
+	var userID uuid.NullUUID
+	var eventType string
+	var metadata []byte
+
+	err := row.Scan(
+		&event.ID,
+		&userID,
+		&eventType,
+		&event.IPAddress,
+		&event.UserAgent,
+		&metadata,
+		&event.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID.Valid {
+		event.UserID = &userID.UUID
+	}
+	event.Type = entities.AuthEventType(eventType)
+	if err := json.Unmarshal(metadata, &event.Metadata); err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
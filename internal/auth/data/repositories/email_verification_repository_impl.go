@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"musicfy/internal/db"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerificationRepositoryImpl implements the EmailVerificationRepository interface for PostgreSQL
+type EmailVerificationRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewEmailVerificationRepository creates a new PostgreSQL email verification repository
+func NewEmailVerificationRepository() repositories.EmailVerificationRepository {
+	return &EmailVerificationRepositoryImpl{
+		db: db.GetDB(),
+	}
+}
+
+// Create inserts a new verification token into the database
+func (r *EmailVerificationRepositoryImpl) Create(token *entities.EmailVerificationToken) error {
+	query := `
+		INSERT INTO email_verification_tokens (id, user_id, email, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		token.ID,
+		token.UserID,
+		token.Email,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+
+	return err
+}
+
+// FindByHash finds a verification token by the hash of its value
+func (r *EmailVerificationRepositoryImpl) FindByHash(tokenHash string) (*entities.EmailVerificationToken, error) {
+	query := `
+		SELECT id, user_id, email, token_hash, expires_at, created_at, used_at
+		FROM email_verification_tokens
+		WHERE token_hash = $1
+	`
+
+	var token entities.EmailVerificationToken
+	var usedAt sql.NullTime
+
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Email,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&usedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Token not found
+		}
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+
+	return &token, nil
+}
+
+// MarkUsed marks a token as used
+func (r *EmailVerificationRepositoryImpl) MarkUsed(id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE email_verification_tokens
+		SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// CountCreatedSince counts the tokens issued to a user since the given time
+func (r *EmailVerificationRepositoryImpl) CountCreatedSince(userID uuid.UUID, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM email_verification_tokens
+		WHERE user_id = $1 AND created_at >= $2
+	`
+
+	var count int
+	err := r.db.QueryRow(query, userID, since).Scan(&count)
+	return count, err
+}
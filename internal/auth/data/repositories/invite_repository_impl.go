@@ -0,0 +1,189 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"musicfy/internal/db"
+
+	"github.com/google/uuid"
+)
+
+// InviteRepositoryImpl implements the InviteRepository interface for PostgreSQL
+type InviteRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewInviteRepository creates a new PostgreSQL invite repository
+func NewInviteRepository() repositories.InviteRepository {
+	return &InviteRepositoryImpl{
+		db: db.GetDB(),
+	}
+}
+
+// inviteColumns lists the invite columns in the order scanInvite expects them
+const inviteColumns = `id, code_hash, code_prefix, note, max_uses, use_count, expires_at, created_by, created_at`
+
+// Create inserts a new invite into the database
+func (r *InviteRepositoryImpl) Create(invite *entities.Invite) error {
+	query := `
+		INSERT INTO invites (id, code_hash, code_prefix, note, max_uses, use_count, expires_at, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	var expiresAt sql.NullTime
+	if invite.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *invite.ExpiresAt, Valid: true}
+	}
+
+	_, err := r.db.Exec(
+		query,
+		invite.ID,
+		invite.CodeHash,
+		invite.CodePrefix,
+		invite.Note,
+		invite.MaxUses,
+		invite.UseCount,
+		expiresAt,
+		invite.CreatedBy,
+		invite.CreatedAt,
+	)
+
+	return err
+}
+
+// FindByID finds an invite by ID
+func (r *InviteRepositoryImpl) FindByID(id uuid.UUID) (*entities.Invite, error) {
+	query := `SELECT ` + inviteColumns + ` FROM invites WHERE id = $1`
+
+	invite, err := r.scanInvite(r.db.QueryRow(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Invite not found
+	}
+	return invite, err
+}
+
+// FindByCodeHash finds an invite by the hash of its code
+func (r *InviteRepositoryImpl) FindByCodeHash(codeHash string) (*entities.Invite, error) {
+	query := `SELECT ` + inviteColumns + ` FROM invites WHERE code_hash = $1`
+
+	invite, err := r.scanInvite(r.db.QueryRow(query, codeHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Invite not found
+	}
+	return invite, err
+}
+
+// List returns a page of invites, newest first
+func (r *InviteRepositoryImpl) List(limit, offset int) ([]*entities.Invite, error) {
+	query := `
+		SELECT ` + inviteColumns + `
+		FROM invites
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invites := []*entities.Invite{}
+	for rows.Next() {
+		invite, err := r.scanInvite(rows)
+		if err != nil {
+			return nil, err
+		}
+		invites = append(invites, invite)
+	}
+
+	return invites, rows.Err()
+}
+
+// redeemInvite uses up one use of an invite and records its redemption by a user within a
+// transaction, which the caller commits or rolls back. It returns false if the invite is
+// used up or expired.
+func redeemInvite(tx *sql.Tx, inviteID, userID uuid.UUID) (bool, error) {
+	// The conditions make concurrent registrations unable to exceed the allowed uses
+	result, err := tx.Exec(`
+		UPDATE invites
+		SET use_count = use_count + 1
+		WHERE id = $1 AND use_count < max_uses AND (expires_at IS NULL OR expires_at > NOW())
+	`, inviteID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected != 1 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO invite_redemptions (id, invite_id, user_id, redeemed_at) VALUES ($1, $2, $3, NOW())",
+		uuid.New(), inviteID, userID,
+	)
+	return err == nil, err
+}
+
+// ListRedemptions returns the redemptions of an invite, oldest first
+func (r *InviteRepositoryImpl) ListRedemptions(inviteID uuid.UUID) ([]*entities.InviteRedemption, error) {
+	query := `
+		SELECT id, invite_id, user_id, redeemed_at
+		FROM invite_redemptions
+		WHERE invite_id = $1
+		ORDER BY redeemed_at
+	`
+
+	rows, err := r.db.Query(query, inviteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	redemptions := []*entities.InviteRedemption{}
+	for rows.Next() {
+		var redemption entities.InviteRedemption
+		if err := rows.Scan(&redemption.ID, &redemption.InviteID, &redemption.UserID, &redemption.RedeemedAt); err != nil {
+			return nil, err
+		}
+		redemptions = append(redemptions, &redemption)
+	}
+
+	return redemptions, rows.Err()
+}
+
+// scanInvite scans an invite row
+func (r *InviteRepositoryImpl) scanInvite(row interface{ Scan(dest ...any) error }) (*entities.Invite, error) {
+	var invite entities.Invite
+	var expiresAt sql.NullTime
+	var createdBy uuid.NullUUID
+
+	err := row.Scan(
+		&invite.ID,
+		&invite.CodeHash,
+		&invite.CodePrefix,
+		&invite.Note,
+		&invite.MaxUses,
+		&invite.UseCount,
+		&expiresAt,
+		&createdBy,
+		&invite.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		invite.ExpiresAt = &expiresAt.Time
+	}
+	if createdBy.Valid {
+		invite.CreatedBy = &createdBy.UUID
+	}
+
+	return &invite, nil
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"musicfy/internal/db"
+	"time"
+)
+
+// LoginThrottleRepositoryImpl implements the LoginThrottleRepository interface for PostgreSQL
+type LoginThrottleRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewLoginThrottleRepository creates a new PostgreSQL login throttle repository
+func NewLoginThrottleRepository() repositories.LoginThrottleRepository {
+	return &LoginThrottleRepositoryImpl{
+		db: db.GetDB(),
+	}
+}
+
+// Find finds the throttle state for a key
+func (r *LoginThrottleRepositoryImpl) Find(key string) (*entities.LoginThrottle, error) {
+	query := `
+		SELECT key, failures, last_failure_at, locked_until
+		FROM login_throttles
+		WHERE key = $1
+	`
+
+	throttle, err := r.scanThrottle(r.db.QueryRow(query, key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // No failed attempts recorded
+	}
+	return throttle, err
+}
+
+// RecordFailure atomically counts a failed attempt and returns the updated state
+func (r *LoginThrottleRepositoryImpl) RecordFailure(key string, resetBefore time.Time) (*entities.LoginThrottle, error) {
+	query := `
+		INSERT INTO login_throttles (key, failures, last_failure_at)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN login_throttles.last_failure_at < $2
+				     AND (login_throttles.locked_until IS NULL OR login_throttles.locked_until < $2)
+				THEN 1
+				ELSE login_throttles.failures + 1
+			END,
+			last_failure_at = NOW()
+		RETURNING key, failures, last_failure_at, locked_until
+	`
+
+	return r.scanThrottle(r.db.QueryRow(query, key, resetBefore))
+}
+
+// Lock blocks login attempts for a key until the given time
+func (r *LoginThrottleRepositoryImpl) Lock(key string, until time.Time) error {
+	_, err := r.db.Exec("UPDATE login_throttles SET locked_until = $1 WHERE key = $2", until, key)
+	return err
+}
+
+// Reset clears failed attempts and any lock for a key
+func (r *LoginThrottleRepositoryImpl) Reset(key string) error {
+	_, err := r.db.Exec("DELETE FROM login_throttles WHERE key = $1", key)
+	return err
+}
+
+// Helper function to scan one throttle row
+func (r *LoginThrottleRepositoryImpl) scanThrottle(row *sql.Row) (*entities.LoginThrottle, error) {
+	var throttle entities.LoginThrottle
+	var lockedUntil sql.NullTime
+
+	if err := row.Scan(&throttle.Key, &throttle.Failures, &throttle.LastFailureAt, &lockedUntil); err != nil {
+		return nil, err
+	}
+
+	if lockedUntil.Valid {
+		throttle.LockedUntil = &lockedUntil.Time
+	}
+
+	return &throttle, nil
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"musicfy/internal/db"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MagicLinkRepositoryImpl implements the MagicLinkRepository interface for PostgreSQL
+type MagicLinkRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewMagicLinkRepository creates a new PostgreSQL magic link repository
+func NewMagicLinkRepository() repositories.MagicLinkRepository {
+	return &MagicLinkRepositoryImpl{
+		db: db.GetDB(),
+	}
+}
+
+// Create inserts a new magic link token into the database
+func (r *MagicLinkRepositoryImpl) Create(token *entities.MagicLinkToken) error {
+	query := `
+		INSERT INTO magic_link_tokens (id, user_id, token_hash, device_hash, ip_address, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		token.ID,
+		token.UserID,
+		token.TokenHash,
+		token.DeviceHash,
+		token.IPAddress,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+
+	return err
+}
+
+// FindByHash finds a magic link token by the hash of its value
+func (r *MagicLinkRepositoryImpl) FindByHash(tokenHash string) (*entities.MagicLinkToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, device_hash, ip_address, expires_at, created_at, used_at
+		FROM magic_link_tokens
+		WHERE token_hash = $1
+	`
+
+	var token entities.MagicLinkToken
+	var usedAt sql.NullTime
+
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.DeviceHash,
+		&token.IPAddress,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&usedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Token not found
+		}
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+
+	return &token, nil
+}
+
+// MarkUsed marks a token as used
+func (r *MagicLinkRepositoryImpl) MarkUsed(id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE magic_link_tokens
+		SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// CountCreatedSince counts the tokens issued to a user since the given time
+func (r *MagicLinkRepositoryImpl) CountCreatedSince(userID uuid.UUID, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM magic_link_tokens
+		WHERE user_id = $1 AND created_at >= $2
+	`
+
+	var count int
+	err := r.db.QueryRow(query, userID, since).Scan(&count)
+	return count, err
+}
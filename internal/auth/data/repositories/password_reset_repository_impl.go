@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"musicfy/internal/db"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetRepositoryImpl implements the PasswordResetRepository interface for PostgreSQL
+type PasswordResetRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewPasswordResetRepository creates a new PostgreSQL password reset repository
+func NewPasswordResetRepository() repositories.PasswordResetRepository {
+	return &PasswordResetRepositoryImpl{
+		db: db.GetDB(),
+	}
+}
+
+// Create inserts a new reset token into the database
+func (r *PasswordResetRepositoryImpl) Create(token *entities.PasswordResetToken) error {
+	query := `
+		INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		token.ID,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+
+	return err
+}
+
+// FindByHash finds a reset token by the hash of its value
+func (r *PasswordResetRepositoryImpl) FindByHash(tokenHash string) (*entities.PasswordResetToken, error) {
+	query := `
+		SELECT id, user_id, token_hash, expires_at, created_at, used_at
+		FROM password_reset_tokens
+		WHERE token_hash = $1
+	`
+
+	var token entities.PasswordResetToken
+	var usedAt sql.NullTime
+
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&usedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Token not found
+		}
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+
+	return &token, nil
+}
+
+// MarkUsed marks a token as used
+func (r *PasswordResetRepositoryImpl) MarkUsed(id uuid.UUID) (bool, error) {
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE id = $1 AND used_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// MarkAllUsedForUser marks every outstanding token of a user as used
+func (r *PasswordResetRepositoryImpl) MarkAllUsedForUser(userID uuid.UUID) error {
+	query := `
+		UPDATE password_reset_tokens
+		SET used_at = NOW()
+		WHERE user_id = $1 AND used_at IS NULL
+	`
+
+	_, err := r.db.Exec(query, userID)
+	return err
+}
+
+// CountCreatedSince counts the tokens issued to a user since the given time
+func (r *PasswordResetRepositoryImpl) CountCreatedSince(userID uuid.UUID, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM password_reset_tokens
+		WHERE user_id = $1 AND created_at >= $2
+	`
+
+	var count int
+	err := r.db.QueryRow(query, userID, since).Scan(&count)
+	return count, err
+}
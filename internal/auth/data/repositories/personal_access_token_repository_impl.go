@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"musicfy/internal/db"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PersonalAccessTokenRepositoryImpl implements the PersonalAccessTokenRepository interface for PostgreSQL
+type PersonalAccessTokenRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewPersonalAccessTokenRepository creates a new PostgreSQL personal access token repository
+func NewPersonalAccessTokenRepository() repositories.PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepositoryImpl{
+		db: db.GetDB(),
+	}
+}
+
+// personalAccessTokenColumns lists the token columns in the order scanToken expects them
+const personalAccessTokenColumns = `id, user_id, name, token_prefix, token_hash, scopes, expires_at, last_used_at, created_at, revoked_at`
+
+// Create inserts a new personal access token into the database
+func (r *PersonalAccessTokenRepositoryImpl) Create(token *entities.PersonalAccessToken) error {
+	query := `
+		INSERT INTO personal_access_tokens (id, user_id, name, token_prefix, token_hash, scopes, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	var expiresAt sql.NullTime
+	if token.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *token.ExpiresAt, Valid: true}
+	}
+
+	_, err := r.db.Exec(
+		query,
+		token.ID,
+		token.UserID,
+		token.Name,
+		token.Prefix,
+		token.TokenHash,
+		pq.Array(permissionsToStrings(token.Scopes)),
+		expiresAt,
+		token.CreatedAt,
+	)
+
+	return err
+}
+
+// FindByHash finds a personal access token by the hash of its value
+func (r *PersonalAccessTokenRepositoryImpl) FindByHash(tokenHash string) (*entities.PersonalAccessToken, error) {
+	query := `SELECT ` + personalAccessTokenColumns + ` FROM personal_access_tokens WHERE token_hash = $1`
+
+	token, err := r.scanToken(r.db.QueryRow(query, tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Token not found
+	}
+	return token, err
+}
+
+// ListByUserID lists the tokens of a user that have not been revoked, newest first
+func (r *PersonalAccessTokenRepositoryImpl) ListByUserID(userID uuid.UUID) ([]*entities.PersonalAccessToken, error) {
+	query := `
+		SELECT ` + personalAccessTokenColumns + `
+		FROM personal_access_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []*entities.PersonalAccessToken{}
+	for rows.Next() {
+		token, err := r.scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+// MarkUsed records when a token was last used
+func (r *PersonalAccessTokenRepositoryImpl) MarkUsed(id uuid.UUID, usedAt time.Time) error {
+	_, err := r.db.Exec("UPDATE personal_access_tokens SET last_used_at = $1 WHERE id = $2", usedAt, id)
+	return err
+}
+
+// Revoke revokes a token belonging to the user
+func (r *PersonalAccessTokenRepositoryImpl) Revoke(id, userID uuid.UUID) (bool, error) {
+	query := `
+		UPDATE personal_access_tokens
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// RevokeAllForUser revokes every active token belonging to a user
+func (r *PersonalAccessTokenRepositoryImpl) RevokeAllForUser(userID uuid.UUID) error {
+	_, err := r.db.Exec("UPDATE personal_access_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL", userID)
+	return err
+}
+
+// scanToken scans a personal access token row
+func (r *PersonalAccessTokenRepositoryImpl) scanToken(row interface{ Scan(dest ...any) error }) (*entities.PersonalAccessToken, error) {
+	var token entities.PersonalAccessToken
+	var scopes pq.StringArray
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Name,
+		&token.Prefix,
+		&token.TokenHash,
+		&scopes,
+		&expiresAt,
+		&lastUsedAt,
+		&token.CreatedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	token.Scopes = make([]entities.Permission, len(scopes))
+	for i, scope := range scopes {
+		token.Scopes[i] = entities.Permission(scope)
+	}
+	if expiresAt.Valid {
+		token.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	return &token, nil
+}
+
+// permissionsToStrings converts permissions to their database representation
+func permissionsToStrings(permissions []entities.Permission) []string {
+	values := make([]string, len(permissions))
+	for i, permission := range permissions {
+		values[i] = string(permission)
+	}
+	return values
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"musicfy/internal/db"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenRepositoryImpl implements the RefreshTokenRepository interface for PostgreSQL
+type RefreshTokenRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewRefreshTokenRepository creates a new PostgreSQL refresh token repository
+func NewRefreshTokenRepository() repositories.RefreshTokenRepository {
+	return &RefreshTokenRepositoryImpl{
+		db: db.GetDB(),
+	}
+}
+
+// Create inserts a new refresh token into the database
+func (r *RefreshTokenRepositoryImpl) Create(token *entities.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		token.ID,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+
+	return err
+}
+
+// FindByHash finds a refresh token by the hash of its value
+func (r *RefreshTokenRepositoryImpl) FindByHash(tokenHash string) (*entities.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, created_at, revoked_at, replaced_by
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	var token entities.RefreshToken
+	var revokedAt sql.NullTime
+	var replacedBy uuid.NullUUID
+
+	err := r.db.QueryRow(query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&revokedAt,
+		&replacedBy,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Token not found
+		}
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	if replacedBy.Valid {
+		token.ReplacedBy = &replacedBy.UUID
+	}
+
+	return &token, nil
+}
+
+// MarkRotated revokes a token in favour of its successor
+func (r *RefreshTokenRepositoryImpl) MarkRotated(id, replacedBy uuid.UUID) (bool, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = NOW(), replaced_by = $1
+		WHERE id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, replacedBy, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// RevokeFamily revokes every active token in a token family
+func (r *RefreshTokenRepositoryImpl) RevokeFamily(familyID uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := r.db.Exec(query, familyID)
+	return err
+}
+
+// RevokeAllForUser revokes every active token belonging to a user
+func (r *RefreshTokenRepositoryImpl) RevokeAllForUser(userID uuid.UUID) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := r.db.Exec(query, userID)
+	return err
+}
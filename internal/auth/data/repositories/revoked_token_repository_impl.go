@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"database/sql"
+	"musicfy/internal/auth/domain/repositories"
+	"musicfy/internal/db"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// negativeCacheTTL bounds how long a "not revoked" answer is trusted before the
+// database is consulted again, so revocations made by other instances are picked up.
+const negativeCacheTTL = 30 * time.Second
+
+// RevokedTokenRepositoryImpl implements the RevokedTokenRepository interface for PostgreSQL
+type RevokedTokenRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewRevokedTokenRepository creates a new PostgreSQL revoked token repository
+// wrapped in an in-memory cache
+func NewRevokedTokenRepository() repositories.RevokedTokenRepository {
+	return NewCachedRevokedTokenRepository(&RevokedTokenRepositoryImpl{
+		db: db.GetDB(),
+	})
+}
+
+// Revoke records an access token as revoked until it expires
+func (r *RevokedTokenRepositoryImpl) Revoke(tokenID, userID uuid.UUID, expiresAt time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (token_id, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (token_id) DO NOTHING
+	`
+
+	_, err := r.db.Exec(query, tokenID, userID, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	// Opportunistically drop entries for tokens that can no longer be used
+	_, err = r.db.Exec("DELETE FROM revoked_tokens WHERE expires_at < NOW()")
+	return err
+}
+
+// IsRevoked reports whether an access token has been revoked
+func (r *RevokedTokenRepositoryImpl) IsRevoked(tokenID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE token_id = $1)`
+
+	var exists bool
+	if err := r.db.QueryRow(query, tokenID).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// CachedRevokedTokenRepository caches lookups of another RevokedTokenRepository in memory.
+// Revoked tokens are remembered until they expire; non-revoked answers are
+// remembered for negativeCacheTTL.
+type CachedRevokedTokenRepository struct {
+	next      repositories.RevokedTokenRepository
+	mu        sync.RWMutex
+	revoked   map[uuid.UUID]time.Time
+	checked   map[uuid.UUID]time.Time
+	lastSweep time.Time
+}
+
+// NewCachedRevokedTokenRepository creates a caching decorator around a revoked token repository
+func NewCachedRevokedTokenRepository(next repositories.RevokedTokenRepository) *CachedRevokedTokenRepository {
+	return &CachedRevokedTokenRepository{
+		next:    next,
+		revoked: make(map[uuid.UUID]time.Time),
+		checked: make(map[uuid.UUID]time.Time),
+	}
+}
+
+// Revoke records an access token as revoked and caches the result
+func (r *CachedRevokedTokenRepository) Revoke(tokenID, userID uuid.UUID, expiresAt time.Time) error {
+	if err := r.next.Revoke(tokenID, userID, expiresAt); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sweep(time.Now())
+	r.revoked[tokenID] = expiresAt
+	delete(r.checked, tokenID)
+
+	return nil
+}
+
+// IsRevoked reports whether an access token has been revoked, consulting the cache first
+func (r *CachedRevokedTokenRepository) IsRevoked(tokenID uuid.UUID) (bool, error) {
+	now := time.Now()
+
+	r.mu.RLock()
+	_, isRevoked := r.revoked[tokenID]
+	checkedUntil, isChecked := r.checked[tokenID]
+	r.mu.RUnlock()
+
+	if isRevoked {
+		return true, nil
+	}
+	if isChecked && now.Before(checkedUntil) {
+		return false, nil
+	}
+
+	revoked, err := r.next.IsRevoked(tokenID)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sweep(now)
+	if revoked {
+		// The expiry is unknown here; revocation is permanent so any retention is safe
+		r.revoked[tokenID] = now.Add(24 * time.Hour)
+	} else {
+		r.checked[tokenID] = now.Add(negativeCacheTTL)
+	}
+
+	return revoked, nil
+}
+
+// sweep periodically removes cache entries that are no longer needed; the caller must hold the write lock
+func (r *CachedRevokedTokenRepository) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < negativeCacheTTL {
+		return
+	}
+	r.lastSweep = now
+
+	for id, expiresAt := range r.revoked {
+		if now.After(expiresAt) {
+			delete(r.revoked, id)
+		}
+	}
+	for id, checkedUntil := range r.checked {
+		if now.After(checkedUntil) {
+			delete(r.checked, id)
+		}
+	}
+}
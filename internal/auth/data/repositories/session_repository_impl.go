@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"musicfy/internal/db"
+
+	"github.com/google/uuid"
+)
+
+// SessionRepositoryImpl implements the SessionRepository interface for PostgreSQL
+type SessionRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewSessionRepository creates a new PostgreSQL session repository
+func NewSessionRepository() repositories.SessionRepository {
+	return &SessionRepositoryImpl{
+		db: db.GetDB(),
+	}
+}
+
+// sessionColumns lists the session columns in the order scanSession expects them
+const sessionColumns = `id, user_id, device_name, user_agent, ip_address, created_at, last_seen_at, expires_at, revoked_at`
+
+// Create inserts a new session into the database
+func (r *SessionRepositoryImpl) Create(session *entities.Session) error {
+	query := `
+		INSERT INTO sessions (` + sessionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULL)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		session.ID,
+		session.UserID,
+		session.DeviceName,
+		session.UserAgent,
+		session.IPAddress,
+		session.CreatedAt,
+		session.LastSeenAt,
+		session.ExpiresAt,
+	)
+
+	return err
+}
+
+// FindByID finds a session by ID
+func (r *SessionRepositoryImpl) FindByID(id uuid.UUID) (*entities.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = $1`
+
+	session, err := r.scanSession(r.db.QueryRow(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // Session not found
+	}
+	return session, err
+}
+
+// ListActiveByUserID lists the active sessions of a user, most recently seen first
+func (r *SessionRepositoryImpl) ListActiveByUserID(userID uuid.UUID) ([]*entities.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*entities.Session{}
+	for rows.Next() {
+		session, err := r.scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// UpdateActivity stores the last-seen time, client address, user agent and expiry of a session
+func (r *SessionRepositoryImpl) UpdateActivity(session *entities.Session) error {
+	query := `
+		UPDATE sessions
+		SET last_seen_at = $1, ip_address = $2, user_agent = $3, expires_at = $4
+		WHERE id = $5
+	`
+
+	_, err := r.db.Exec(query, session.LastSeenAt, session.IPAddress, session.UserAgent, session.ExpiresAt, session.ID)
+	return err
+}
+
+// Revoke revokes a session
+func (r *SessionRepositoryImpl) Revoke(id uuid.UUID) error {
+	_, err := r.db.Exec("UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL", id)
+	return err
+}
+
+// RevokeAllForUser revokes every active session belonging to a user
+func (r *SessionRepositoryImpl) RevokeAllForUser(userID uuid.UUID) error {
+	_, err := r.db.Exec("UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL", userID)
+	return err
+}
+
+// scanSession scans a session row
+func (r *SessionRepositoryImpl) scanSession(row interface{ Scan(dest ...any) error }) (*entities.Session, error) {
+	var session entities.Session
+	var revokedAt sql.NullTime
+
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.DeviceName,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+
+	return &session, nil
+}
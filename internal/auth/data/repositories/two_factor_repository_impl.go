@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"musicfy/internal/db"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorRepositoryImpl implements the TwoFactorRepository interface for PostgreSQL
+type TwoFactorRepositoryImpl struct {
+	db *sql.DB
+}
+
+// NewTwoFactorRepository creates a new PostgreSQL two-factor repository
+func NewTwoFactorRepository() repositories.TwoFactorRepository {
+	return &TwoFactorRepositoryImpl{
+		db: db.GetDB(),
+	}
+}
+
+// FindByUserID finds the TOTP enrollment of a user
+func (r *TwoFactorRepositoryImpl) FindByUserID(userID uuid.UUID) (*entities.TwoFactor, error) {
+	query := `
+		SELECT user_id, secret, confirmed_at, last_used_step, created_at
+		FROM user_two_factor
+		WHERE user_id = $1
+	`
+
+	var twoFactor entities.TwoFactor
+	var confirmedAt sql.NullTime
+
+	err := r.db.QueryRow(query, userID).Scan(
+		&twoFactor.UserID,
+		&twoFactor.Secret,
+		&confirmedAt,
+		&twoFactor.LastUsedStep,
+		&twoFactor.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Not enrolled
+		}
+		return nil, err
+	}
+
+	if confirmedAt.Valid {
+		twoFactor.ConfirmedAt = &confirmedAt.Time
+	}
+
+	return &twoFactor, nil
+}
+
+// SavePending stores a new unconfirmed enrollment, replacing any earlier unconfirmed one
+func (r *TwoFactorRepositoryImpl) SavePending(twoFactor *entities.TwoFactor) error {
+	query := `
+		INSERT INTO user_two_factor (user_id, secret, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, created_at = EXCLUDED.created_at, last_used_step = 0
+		WHERE user_two_factor.confirmed_at IS NULL
+	`
+
+	_, err := r.db.Exec(query, twoFactor.UserID, twoFactor.Secret, twoFactor.CreatedAt)
+	return err
+}
+
+// Confirm enables a pending enrollment and records the time step of the confirming code
+func (r *TwoFactorRepositoryImpl) Confirm(userID uuid.UUID, step int64) error {
+	query := `
+		UPDATE user_two_factor
+		SET confirmed_at = NOW(), last_used_step = $1
+		WHERE user_id = $2 AND confirmed_at IS NULL
+	`
+
+	_, err := r.db.Exec(query, step, userID)
+	return err
+}
+
+// UseStep records a time step as used
+func (r *TwoFactorRepositoryImpl) UseStep(userID uuid.UUID, step int64) (bool, error) {
+	query := `
+		UPDATE user_two_factor
+		SET last_used_step = $1
+		WHERE user_id = $2 AND last_used_step < $1
+	`
+
+	result, err := r.db.Exec(query, step, userID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// Delete removes the enrollment and all recovery codes of a user
+func (r *TwoFactorRepositoryImpl) Delete(userID uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM two_factor_recovery_codes WHERE user_id = $1", userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM user_two_factor WHERE user_id = $1", userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceRecoveryCodes discards the recovery codes of a user and stores new code hashes
+func (r *TwoFactorRepositoryImpl) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM two_factor_recovery_codes WHERE user_id = $1", userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, codeHash := range codeHashes {
+		_, err := tx.Exec(
+			"INSERT INTO two_factor_recovery_codes (id, user_id, code_hash) VALUES ($1, $2, $3)",
+			uuid.New(), userID, codeHash,
+		)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UseRecoveryCode marks an unused recovery code as used
+func (r *TwoFactorRepositoryImpl) UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	query := `
+		UPDATE two_factor_recovery_codes
+		SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	result, err := r.db.Exec(query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
@@ -3,14 +3,31 @@ package repositories
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"musicfy/internal/auth/domain"
 	"musicfy/internal/auth/domain/entities"
 	"musicfy/internal/auth/domain/repositories"
 	"musicfy/internal/db"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// userColumns lists the columns scanned by scanUser, in order
+const userColumns = `id, first_name, last_name, username, email, date_of_birth, password_hash, roles, status, created_at, updated_at,
+		email_verified_at, tokens_valid_after, avatar_key`
+
+// Unique indexes on the users table, see migration 012
+const (
+	usernameUniqueIndex = "users_username_lower_key"
+	emailUniqueIndex    = "users_email_lower_key"
+)
+
+// uniqueViolationCode is the Postgres error code for unique constraint violations
+const uniqueViolationCode = "23505"
+
 // UserRepositoryImpl implements the UserRepository interface for PostgreSQL
 type UserRepositoryImpl struct {
 	db *sql.DB
@@ -23,66 +40,92 @@ func NewUserRepository() repositories.UserRepository {
 	}
 }
 
-// Create inserts a new user into the database
+// Create inserts a new user into the database. A taken username or email is reported
+// as ErrUsernameExists or ErrEmailExists.
 func (r *UserRepositoryImpl) Create(user *entities.User) error {
+	return insertUser(r.db, user)
+}
+
+// CreateWithInvite inserts a new user and uses up one use of an invite in one transaction.
+// It returns false, without creating the user, if the invite cannot be redeemed.
+func (r *UserRepositoryImpl) CreateWithInvite(user *entities.User, inviteID uuid.UUID) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	if err := insertUser(tx, user); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	redeemed, err := redeemInvite(tx, inviteID, user.ID)
+	if err != nil || !redeemed {
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// insertUser inserts a user through the database or a transaction, translating unique
+// violations
+func insertUser(exec execer, user *entities.User) error {
 	query := `
-		INSERT INTO users (id, first_name, last_name, username, email, age, password_hash, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		INSERT INTO users (id, first_name, last_name, username, email, date_of_birth, password_hash, roles, status, created_at,
+		                   updated_at, email_verified_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 	`
 
-	_, err := r.db.Exec(
+	_, err := exec.Exec(
 		query,
 		user.ID,
 		user.FirstName,
 		user.LastName,
 		user.Username,
 		user.Email,
-		user.Age,
+		user.DateOfBirth.Format(entities.DateOfBirthLayout),
 		user.PasswordHash,
+		pq.Array(rolesToStrings(user.Roles)),
+		string(user.Status),
 		user.CreatedAt,
 		user.UpdatedAt,
+		user.EmailVerifiedAt,
 	)
 
-	return err
+	return translateUserConstraintError(err)
 }
 
-// FindByUsername finds a user by username
+// FindByUsername finds a user by username, ignoring case
 func (r *UserRepositoryImpl) FindByUsername(username string) (*entities.User, error) {
 	query := `
-		SELECT id, first_name, last_name, username, email, age, password_hash, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
-		WHERE username = $1
+		WHERE lower(username) = lower($1)
 	`
 
 	return r.findOneByQuery(query, username)
 }
 
-// FindByEmail finds a user by email
+// FindByEmail finds a user by email, ignoring case
 func (r *UserRepositoryImpl) FindByEmail(email string) (*entities.User, error) {
 	query := `
-		SELECT id, first_name, last_name, username, email, age, password_hash, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
-		WHERE email = $1
+		WHERE lower(email) = lower($1)
 	`
 
 	return r.findOneByQuery(query, email)
 }
 
-// FindByUsernameOrEmail finds a user by username or email
-func (r *UserRepositoryImpl) FindByUsernameOrEmail(usernameOrEmail string) (*entities.User, error) {
-	query := `
-		SELECT id, first_name, last_name, username, email, age, password_hash, created_at, updated_at
-		FROM users
-		WHERE username = $1 OR email = $1
-	`
-
-	return r.findOneByQuery(query, usernameOrEmail)
-}
-
 // FindByID finds a user by ID
 func (r *UserRepositoryImpl) FindByID(id uuid.UUID) (*entities.User, error) {
 	query := `
-		SELECT id, first_name, last_name, username, email, age, password_hash, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE id = $1
 	`
@@ -90,13 +133,15 @@ func (r *UserRepositoryImpl) FindByID(id uuid.UUID) (*entities.User, error) {
 	return r.findOneByQuery(query, id)
 }
 
-// Update updates an existing user in the database
+// Update updates an existing user in the database. A taken username or email is reported
+// as ErrUsernameExists or ErrEmailExists. The avatar key is only changed by SetAvatarKey.
 func (r *UserRepositoryImpl) Update(user *entities.User) error {
 	query := `
 		UPDATE users
-		SET first_name = $1, last_name = $2, username = $3, email = $4, 
-		    age = $5, password_hash = $6, updated_at = $7
-		WHERE id = $8
+		SET first_name = $1, last_name = $2, username = $3, email = $4,
+		    date_of_birth = $5, password_hash = $6, roles = $7, status = $8, updated_at = $9, email_verified_at = $10,
+		    tokens_valid_after = $11
+		WHERE id = $12
 	`
 
 	user.UpdatedAt = time.Now()
@@ -107,38 +152,233 @@ func (r *UserRepositoryImpl) Update(user *entities.User) error {
 		user.LastName,
 		user.Username,
 		user.Email,
-		user.Age,
+		user.DateOfBirth.Format(entities.DateOfBirthLayout),
 		user.PasswordHash,
+		pq.Array(rolesToStrings(user.Roles)),
+		string(user.Status),
 		user.UpdatedAt,
+		user.EmailVerifiedAt,
+		user.TokensValidAfter,
 		user.ID,
 	)
 
+	return translateUserConstraintError(err)
+}
+
+// Unsuspend makes a suspended user active again; it reports whether the user was suspended
+func (r *UserRepositoryImpl) Unsuspend(id uuid.UUID) (bool, error) {
+	result, err := r.db.Exec(
+		"UPDATE users SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4",
+		string(entities.UserStatusActive), time.Now(), id, string(entities.UserStatusSuspended),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// UpdatePasswordHash replaces the password hash of a user, provided it still equals
+// oldHash; it reports whether the hash was replaced
+func (r *UserRepositoryImpl) UpdatePasswordHash(id uuid.UUID, newHash, oldHash string) (bool, error) {
+	result, err := r.db.Exec(
+		"UPDATE users SET password_hash = $1, updated_at = $2 WHERE id = $3 AND password_hash = $4",
+		newHash, time.Now(), id, oldHash,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// SetEmailVerified marks the email address of a user as verified at the given time,
+// provided it is still email and not verified yet
+func (r *UserRepositoryImpl) SetEmailVerified(id uuid.UUID, email string, at time.Time) error {
+	_, err := r.db.Exec(
+		"UPDATE users SET email_verified_at = $1, updated_at = $2 WHERE id = $3 AND email = $4 AND email_verified_at IS NULL",
+		at, time.Now(), id, email,
+	)
+	return err
+}
+
+// SetAvatarKey replaces the avatar key of a user, provided it still equals previousKey;
+// it reports whether the key was replaced
+func (r *UserRepositoryImpl) SetAvatarKey(id uuid.UUID, key, previousKey string) (bool, error) {
+	result, err := r.db.Exec(
+		"UPDATE users SET avatar_key = $1, updated_at = $2 WHERE id = $3 AND avatar_key = $4",
+		key, time.Now(), id, previousKey,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// List returns a page of the users matching a filter, newest first, and the number of
+// matching users
+func (r *UserRepositoryImpl) List(filter repositories.UserFilter) ([]*entities.User, int, error) {
+	// Build conditions from the filter
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Search != "" {
+		addCondition(`(username ILIKE $%[1]d OR email ILIKE $%[1]d OR first_name || ' ' || last_name ILIKE $%[1]d)`,
+			"%"+escapeLikePattern(filter.Search)+"%")
+	}
+	if filter.Status != "" {
+		addCondition("status = $%d", string(filter.Status))
+	}
+	if filter.Role != "" {
+		addCondition("$%d = ANY(roles)", string(filter.Role))
+	}
+	if filter.EmailVerified != nil {
+		if *filter.EmailVerified {
+			conditions = append(conditions, "email_verified_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, "email_verified_at IS NULL")
+		}
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+
+	// Count all matches for pagination
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM users`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users` + where +
+		fmt.Sprintf(` ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d`, len(args)+1, len(args)+2)
+
+	rows, err := r.db.Query(query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []*entities.User{}
+	for rows.Next() {
+		user, err := r.scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+
+	return users, total, rows.Err()
+}
+
+// Delete removes a user; dependent rows are removed by ON DELETE CASCADE
+func (r *UserRepositoryImpl) Delete(id uuid.UUID) error {
+	_, err := r.db.Exec("DELETE FROM users WHERE id = $1", id)
 	return err
 }
 
 // Helper function to find one user by a query
 func (r *UserRepositoryImpl) findOneByQuery(query string, args ...interface{}) (*entities.User, error) {
+	user, err := r.scanUser(r.db.QueryRow(query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil // User not found
+	}
+	return user, err
+}
+
+// scanUser scans a user row
+func (r *UserRepositoryImpl) scanUser(row interface{ Scan(dest ...any) error }) (*entities.User, error) {
 	var user entities.User
+	var roles pq.StringArray
+	var status string
+	var emailVerifiedAt, tokensValidAfter sql.NullTime
 
-	row := r.db.QueryRow(query, args...)
 	err := row.Scan(
 		&user.ID,
 		&user.FirstName,
 		&user.LastName,
 		&user.Username,
 		&user.Email,
-		&user.Age,
+		&user.DateOfBirth,
 		&user.PasswordHash,
+		&roles,
+		&status,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&emailVerifiedAt,
+		&tokensValidAfter,
+		&user.AvatarKey,
 	)
-
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil // User not found
-		}
 		return nil, err
 	}
 
+	user.Roles = stringsToRoles(roles)
+	user.Status = entities.UserStatus(status)
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
+	if tokensValidAfter.Valid {
+		user.TokensValidAfter = &tokensValidAfter.Time
+	}
+
 	return &user, nil
 }
+
+// translateUserConstraintError maps unique violations on username and email to domain errors
+func translateUserConstraintError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != uniqueViolationCode {
+		return err
+	}
+
+	switch pqErr.Constraint {
+	case usernameUniqueIndex:
+		return domain.ErrUsernameExists
+	case emailUniqueIndex:
+		return domain.ErrEmailExists
+	default:
+		return err
+	}
+}
+
+// escapeLikePattern escapes the wildcard characters of a LIKE pattern
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// rolesToStrings converts roles to their database representation
+func rolesToStrings(roles []entities.Role) []string {
+	values := make([]string, len(roles))
+	for i, role := range roles {
+		values[i] = string(role)
+	}
+	return values
+}
+
+// stringsToRoles converts database values to roles
+func stringsToRoles(values []string) []entities.Role {
+	roles := make([]entities.Role, len(values))
+	for i, value := range values {
+		roles[i] = entities.Role(value)
+	}
+	return roles
+}
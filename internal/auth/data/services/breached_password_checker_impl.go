@@ -0,0 +1,62 @@
+package services
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"musicfy/internal/config"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// breachedHashPrefixLength is the number of SHA-1 hex digits that name a range file
+const breachedHashPrefixLength = 5
+
+// BreachedPasswordCheckerImpl looks passwords up in a local copy of a breached password list
+// split k-anonymity style: the file <dir>/<first 5 SHA-1 hex digits>.txt holds one
+// "<remaining 35 hex digits>:<count>" line per breached password in that range, which is
+// the layout of the Pwned Passwords range API and its downloader.
+type BreachedPasswordCheckerImpl struct {
+	dir string
+}
+
+// NewBreachedPasswordChecker creates a checker reading BREACHED_PASSWORDS_DIR; without a
+// directory no password is reported as breached
+func NewBreachedPasswordChecker() *BreachedPasswordCheckerImpl {
+	return &BreachedPasswordCheckerImpl{
+		dir: config.AppConfig.PasswordConfig.BreachedPasswordsDir,
+	}
+}
+
+// IsBreached reports whether the password's SHA-1 hash is listed in its range file
+func (c *BreachedPasswordCheckerImpl) IsBreached(password string) (bool, error) {
+	if c.dir == "" {
+		return false, nil
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:breachedHashPrefixLength], hash[breachedHashPrefixLength:]
+
+	file, err := os.Open(filepath.Join(c.dir, prefix+".txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil // No breached password in this range
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), ":")
+		if strings.EqualFold(strings.TrimSpace(line), suffix) {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+const (
+	passwordRange  = "5BAA6"
+	passwordSuffix = "1E4C9B93F3F0682250B6CF8331B7EE68FD8"
+)
+
+func writeRangeFile(t *testing.T, dir, prefix, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, prefix+".txt"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBreachedPasswordChecker(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		password string
+		want     bool
+	}{
+		{name: "listed", content: "003D68EB55068C33ACE09247EE4C639306B:3\n" + passwordSuffix + ":9659365\n", password: "password", want: true},
+		{name: "listed in lowercase", content: "1e4c9b93f3f0682250b6cf8331b7ee68fd8:12\n", password: "password", want: true},
+		{name: "Windows line endings", content: "003D68EB55068C33ACE09247EE4C639306B:3\r\n" + passwordSuffix + ":1\r\n", password: "password", want: true},
+		{name: "listed without a count", content: passwordSuffix + "\n", password: "password", want: true},
+		{name: "other suffixes in the range", content: "003D68EB55068C33ACE09247EE4C639306B:3\n1E4C9B93F3F0682250B6CF8331B7EE68FD9:1\n", password: "password", want: false},
+		{name: "suffix prefix only", content: "1E4C9B93F3F0682250B6CF8331B7EE68FD:1\n", password: "password", want: false},
+		{name: "range file of another password", content: passwordSuffix + ":1\n", password: "Password", want: false},
+		{name: "empty range file", content: "", password: "password", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeRangeFile(t, dir, passwordRange, tt.content)
+
+			got, err := (&BreachedPasswordCheckerImpl{dir: dir}).IsBreached(tt.password)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("IsBreached(%q) = %v, want %v", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreachedPasswordCheckerMissingRange(t *testing.T) {
+	got, err := (&BreachedPasswordCheckerImpl{dir: t.TempDir()}).IsBreached("password")
+	if got || err != nil {
+		t.Errorf("IsBreached() without a range file = %v, %v", got, err)
+	}
+}
+
+func TestBreachedPasswordCheckerDisabled(t *testing.T) {
+	got, err := (&BreachedPasswordCheckerImpl{}).IsBreached("password")
+	if got || err != nil {
+		t.Errorf("IsBreached() without a directory = %v, %v", got, err)
+	}
+}
+
+func TestBreachedPasswordCheckerUnreadableRange(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, passwordRange+".txt"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := (&BreachedPasswordCheckerImpl{dir: dir}).IsBreached("password"); err == nil {
+		t.Error("IsBreached() with an unreadable range file succeeded")
+	}
+}
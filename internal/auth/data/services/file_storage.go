@@ -0,0 +1,24 @@
+package services
+
+import (
+	"log"
+	"musicfy/internal/auth/domain/usecases"
+	"musicfy/internal/config"
+)
+
+// NewFileStorage creates the file storage selected by the STORAGE_DRIVER setting
+func NewFileStorage() usecases.FileStorage {
+	cfg := config.AppConfig.StorageConfig
+
+	switch cfg.Driver {
+	case "local", "":
+		storage, err := NewLocalFileStorage(cfg.LocalDir, cfg.PublicURL)
+		if err != nil {
+			log.Fatalf("Failed to create storage directory: %v", err)
+		}
+		return storage
+	default:
+		log.Fatalf("Unknown storage driver: %s", cfg.Driver)
+		return nil
+	}
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // register GIF decoding
+	"image/jpeg"
+	_ "image/png" // register PNG decoding
+	"log"
+	"math"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/usecases"
+	"musicfy/internal/config"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// thumbnailQuality is the JPEG quality of resized images
+const thumbnailQuality = 85
+
+// ImageProcessorImpl implements the ImageProcessor interface with the standard library
+// decoders and a separable tent filter for resampling. A decoded image can take several bytes
+// per pixel, so only a limited number of images are processed at once.
+type ImageProcessorImpl struct {
+	slots chan struct{}
+}
+
+// NewImageProcessor creates a new image processor from the application configuration
+func NewImageProcessor() *ImageProcessorImpl {
+	concurrency := config.AppConfig.AuthConfig.AvatarMaxConcurrentProcessing
+	if concurrency < 1 {
+		log.Fatalf("AVATAR_MAX_CONCURRENT_PROCESSING must be at least 1, got %d", concurrency)
+	}
+	return &ImageProcessorImpl{slots: make(chan struct{}, concurrency)}
+}
+
+// Inspect sniffs the content type of an image and reads the dimensions from its header
+func (p *ImageProcessorImpl) Inspect(content []byte) (usecases.ImageInfo, error) {
+	info := usecases.ImageInfo{ContentType: mimetype.Detect(content).String()}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return info, domain.ErrUnsupportedImage
+	}
+	info.Width, info.Height = config.Width, config.Height
+
+	return info, nil
+}
+
+// SquareThumbnails crops an image to a centred square and scales it to each size. Transparent
+// areas are flattened onto white, since JPEG has no alpha channel.
+func (p *ImageProcessorImpl) SquareThumbnails(content []byte, sizes []int) (map[int][]byte, error) {
+	p.slots <- struct{}{}
+	defer func() { <-p.slots }()
+
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, domain.ErrUnsupportedImage
+	}
+
+	// Locate the centred square
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	origin := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+
+	// Horizontal pass for every size, flattening one source row at a time instead of copying
+	// the whole square: side rows of size pixels, three channels each
+	weights := make([][]resampleWeight, len(sizes))
+	rows := make([][]float32, len(sizes))
+	for i, size := range sizes {
+		weights[i] = resampleWeights(side, size)
+		rows[i] = make([]float32, side*size*3)
+	}
+	line := image.NewRGBA(image.Rect(0, 0, side, 1))
+	for y := 0; y < side; y++ {
+		draw.Draw(line, line.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(line, line.Bounds(), img, origin.Add(image.Pt(0, y)), draw.Over)
+		for i, size := range sizes {
+			resampleRow(line.Pix, weights[i], rows[i][y*size*3:])
+		}
+	}
+
+	thumbnails := make(map[int][]byte, len(sizes))
+	for i, size := range sizes {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resampleColumns(rows[i], weights[i], size), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+			return nil, err
+		}
+		thumbnails[size] = buf.Bytes()
+	}
+
+	return thumbnails, nil
+}
+
+// resampleRow scales one opaque RGBA row and writes the result to out, three channels per pixel
+func resampleRow(line []uint8, weights []resampleWeight, out []float32) {
+	for x, w := range weights {
+		var r, g, b float64
+		for i, weight := range w.weights {
+			offset := (w.start + i) * 4
+			r += float64(line[offset]) * weight
+			g += float64(line[offset+1]) * weight
+			b += float64(line[offset+2]) * weight
+		}
+		out[x*3], out[x*3+1], out[x*3+2] = float32(r), float32(g), float32(b)
+	}
+}
+
+// resampleColumns scales the horizontally resampled rows of a square vertically to
+// size×size pixels
+func resampleColumns(rows []float32, weights []resampleWeight, size int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y, w := range weights {
+		for x := 0; x < size; x++ {
+			var r, g, b float64
+			for i, weight := range w.weights {
+				offset := ((w.start+i)*size + x) * 3
+				r += float64(rows[offset]) * weight
+				g += float64(rows[offset+1]) * weight
+				b += float64(rows[offset+2]) * weight
+			}
+			offset := y*dst.Stride + x*4
+			dst.Pix[offset] = clampChannel(r)
+			dst.Pix[offset+1] = clampChannel(g)
+			dst.Pix[offset+2] = clampChannel(b)
+			dst.Pix[offset+3] = 0xff
+		}
+	}
+
+	return dst
+}
+
+// resampleWeight lists the source pixels, starting at start, that make up one output pixel
+type resampleWeight struct {
+	start   int
+	weights []float64
+}
+
+// resampleWeights computes the tent filter weights that map srcLen pixels onto dstLen pixels.
+// When shrinking, the filter is widened to cover every source pixel, which avoids aliasing.
+func resampleWeights(srcLen, dstLen int) []resampleWeight {
+	scale := float64(srcLen) / float64(dstLen)
+	support := math.Max(scale, 1)
+
+	weights := make([]resampleWeight, dstLen)
+	for i := range weights {
+		center := (float64(i) + 0.5) * scale
+		start := max(int(math.Floor(center-support)), 0)
+		end := min(int(math.Ceil(center+support)), srcLen)
+
+		w := resampleWeight{start: start, weights: make([]float64, end-start)}
+		var sum float64
+		for j := start; j < end; j++ {
+			weight := 1 - math.Abs((float64(j)+0.5-center)/support)
+			if weight > 0 {
+				w.weights[j-start] = weight
+				sum += weight
+			}
+		}
+		if sum == 0 {
+			// Only possible for degenerate sizes; fall back to the nearest pixel
+			w.start, w.weights = min(int(center), srcLen-1), []float64{1}
+		} else {
+			for j := range w.weights {
+				w.weights[j] /= sum
+			}
+		}
+		weights[i] = w
+	}
+
+	return weights
+}
+
+// clampChannel rounds a resampled channel value to a byte
+func clampChannel(v float64) uint8 {
+	return uint8(math.Min(math.Max(math.Round(v), 0), 255))
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"musicfy/internal/auth/domain"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSAKeyBits is the smallest RSA modulus accepted for signing keys
+const minRSAKeyBits = 2048
+
+// signingKey is one key of the JWT key set. Keys without a private part only verify tokens;
+// they let a new key be published before it signs and an old key keep verifying after
+// its private part has been destroyed.
+type signingKey struct {
+	ID        string
+	Method    jwt.SigningMethod
+	SignKey   interface{}
+	VerifyKey interface{}
+	// Public is set for asymmetric keys, which are published in the JWKS
+	Public crypto.PublicKey
+}
+
+// keySet holds every key tokens may be verified with and the one new tokens are signed with
+type keySet struct {
+	keys   map[string]*signingKey
+	active *signingKey
+}
+
+// newHMACKeySet returns a key set with a single shared secret
+func newHMACKeySet(secret []byte) *keySet {
+	key := &signingKey{Method: jwt.SigningMethodHS256, SignKey: secret, VerifyKey: secret}
+	return &keySet{keys: map[string]*signingKey{"": key}, active: key}
+}
+
+// loadKeySet reads every *.pem file of dir as a key whose kid is the file name without
+// extension. The key named activeID signs new tokens; when activeID is empty the private
+// key with the greatest kid does, so date-based names rotate automatically.
+func loadKeySet(dir, activeID string) (*keySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	set := &keySet{keys: make(map[string]*signingKey, len(paths))}
+	for _, path := range paths {
+		kid := strings.TrimSuffix(filepath.Base(path), ".pem")
+		key, err := readPEMKey(path, kid)
+		if err != nil {
+			return nil, err
+		}
+		set.keys[kid] = key
+	}
+
+	if activeID == "" {
+		kids := make([]string, 0, len(set.keys))
+		for kid, key := range set.keys {
+			if key.SignKey != nil {
+				kids = append(kids, kid)
+			}
+		}
+		if len(kids) == 0 {
+			return nil, fmt.Errorf("no private signing key found in %s", dir)
+		}
+		sort.Strings(kids)
+		activeID = kids[len(kids)-1]
+	}
+
+	set.active = set.keys[activeID]
+	if set.active == nil || set.active.SignKey == nil {
+		return nil, fmt.Errorf("signing key %q not found in %s", activeID, dir)
+	}
+	return set, nil
+}
+
+// readPEMKey parses an RSA or Ed25519 key, private (PKCS#8 or PKCS#1) or public (PKIX)
+func readPEMKey(path, kid string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data", path)
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		err = fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	key := &signingKey{ID: kid}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.SignKey, key.VerifyKey, key.Public = jwt.SigningMethodRS256, k, &k.PublicKey, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.VerifyKey, key.Public = jwt.SigningMethodRS256, k, k
+	case ed25519.PrivateKey:
+		public := k.Public().(ed25519.PublicKey)
+		key.Method, key.SignKey, key.VerifyKey, key.Public = jwt.SigningMethodEdDSA, k, public, public
+	case ed25519.PublicKey:
+		key.Method, key.VerifyKey, key.Public = jwt.SigningMethodEdDSA, k, k
+	default:
+		return nil, fmt.Errorf("%s: only RSA and Ed25519 keys are supported", path)
+	}
+
+	if public, ok := key.Public.(*rsa.PublicKey); ok && public.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("%s: RSA keys must be at least %d bits", path, minRSAKeyBits)
+	}
+	return key, nil
+}
+
+// keyStore guards the current key set so that it can be swapped while tokens are processed
+type keyStore struct {
+	mu  sync.RWMutex
+	set *keySet
+}
+
+// current returns the key set in use
+func (s *keyStore) current() *keySet {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.set
+}
+
+// replace swaps in a new key set
+func (s *keyStore) replace(set *keySet) {
+	s.mu.Lock()
+	s.set = set
+	s.mu.Unlock()
+}
+
+// verificationKey looks up the key named by a token's kid header and pins its algorithm,
+// so that a token cannot choose how it is verified
+func (set *keySet) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := set.keys[kid]
+	if !ok {
+		return nil, domain.ErrTokenSignatureInvalid
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, domain.ErrTokenAlgorithm
+	}
+	return key.VerifyKey, nil
+}
@@ -1,9 +1,17 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"log"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
 	"musicfy/internal/auth/domain/usecases"
 	"musicfy/internal/config"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -12,69 +20,281 @@ import (
 
 // JWTServiceImpl implements the JWTService interface
 type JWTServiceImpl struct {
-	jwtKey      []byte
-	expiryHours int
+	keys          *keyStore
+	issuer        string
+	audience      string
+	clockSkew     time.Duration
+	accessExpiry  time.Duration
+	refreshExpiry time.Duration
 }
 
+// Token uses distinguish access tokens from 2FA challenge tokens signed with the same key
+const (
+	tokenUseAccess    = "access"
+	tokenUseChallenge = "2fa_challenge"
+
+	challengeExpiry = 5 * time.Minute
+)
+
 // jwtClaims is the internal claims structure for JWT
 type jwtClaims struct {
-	UserID   uuid.UUID `json:"user_id"`
-	Username string    `json:"username"`
+	UserID    uuid.UUID `json:"user_id"`
+	SessionID uuid.UUID `json:"sid,omitempty"`
+	Username  string    `json:"username,omitempty"`
+	Roles     []string  `json:"roles,omitempty"`
+	TokenUse  string    `json:"token_use"`
 	jwt.RegisteredClaims
 }
 
-// NewJWTService creates a new JWT service
+// NewJWTService creates a new JWT service. Tokens are signed with RS256 or EdDSA keys
+// from JWT_SIGNING_KEYS_DIR when it is set, and with the HS256 secret otherwise.
 func NewJWTService() *JWTServiceImpl {
 	// Ensure config is loaded
 	if config.AppConfig.JWTConfig.Secret == "" {
 		config.LoadConfig()
 	}
+	cfg := config.AppConfig.JWTConfig
+
+	accessExpiryMinutes := cfg.AccessExpiryMinutes
+	if accessExpiryMinutes <= 0 {
+		accessExpiryMinutes = 15 // Default to 15 minutes
+	}
+
+	refreshExpiryHours := cfg.RefreshExpiryHours
+	if refreshExpiryHours <= 0 {
+		refreshExpiryHours = 720 // Default to 30 days
+	}
 
-	jwtSecret := config.AppConfig.JWTConfig.Secret
-	if jwtSecret == "" {
-		log.Fatalf("JWT secret not configured")
+	service := &JWTServiceImpl{
+		keys:          &keyStore{},
+		issuer:        cfg.Issuer,
+		audience:      cfg.Audience,
+		clockSkew:     time.Duration(cfg.ClockSkewSeconds) * time.Second,
+		accessExpiry:  time.Duration(accessExpiryMinutes) * time.Minute,
+		refreshExpiry: time.Duration(refreshExpiryHours) * time.Hour,
 	}
 
-	expiryHours := config.AppConfig.JWTConfig.ExpiryHours
-	if expiryHours <= 0 {
-		expiryHours = 24 // Default to 24 hours
+	if cfg.SigningKeysDir == "" {
+		if cfg.Secret == "" {
+			log.Fatalf("JWT secret not configured")
+		}
+		service.keys.replace(newHMACKeySet([]byte(cfg.Secret)))
+		return service
 	}
 
-	return &JWTServiceImpl{
-		jwtKey:      []byte(jwtSecret),
-		expiryHours: expiryHours,
+	set, err := loadKeySet(cfg.SigningKeysDir, cfg.ActiveKeyID)
+	if err != nil {
+		log.Fatalf("Failed to load JWT signing keys: %v", err)
 	}
+	service.keys.replace(set)
+	log.Printf("Signing JWTs with key %q (%s)", set.active.ID, set.active.Method.Alg())
+
+	// Pick up added, promoted and retired keys without a restart
+	if cfg.KeyReloadSeconds > 0 {
+		go service.watchKeys(cfg.SigningKeysDir, cfg.ActiveKeyID, time.Duration(cfg.KeyReloadSeconds)*time.Second)
+	}
+
+	return service
 }
 
-// GenerateToken creates a new JWT token for a user
-func (s *JWTServiceImpl) GenerateToken(userID uuid.UUID, username string) (string, error) {
-	expirationTime := time.Now().Add(time.Duration(s.expiryHours) * time.Hour)
-	claims := &jwtClaims{
-		UserID:   userID,
-		Username: username,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-		},
+// watchKeys periodically reloads the key directory. A directory that fails to load
+// leaves the previous key set in place.
+func (s *JWTServiceImpl) watchKeys(dir, activeID string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		set, err := loadKeySet(dir, activeID)
+		if err != nil {
+			log.Printf("Failed to reload JWT signing keys: %v", err)
+			continue
+		}
+		if previous := s.keys.current(); previous.active.ID != set.active.ID {
+			log.Printf("Signing JWTs with key %q (%s)", set.active.ID, set.active.Method.Alg())
+		}
+		s.keys.replace(set)
+	}
+}
+
+// GenerateToken creates a new JWT token for a user, embedding their roles and session
+func (s *JWTServiceImpl) GenerateToken(user *entities.User, sessionID uuid.UUID) (string, error) {
+	roles := make([]string, len(user.Roles))
+	for i, role := range user.Roles {
+		roles[i] = string(role)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.jwtKey)
+	return s.sign(&jwtClaims{
+		UserID:    user.ID,
+		SessionID: sessionID,
+		Username:  user.Username,
+		Roles:     roles,
+		TokenUse:  tokenUseAccess,
+	}, s.accessExpiry)
 }
 
-// ValidateToken validates a JWT token and returns the claims
+// ValidateToken validates a JWT access token and returns the claims
 func (s *JWTServiceImpl) ValidateToken(tokenString string) (*usecases.JWTClaims, error) {
+	return s.parse(tokenString, tokenUseAccess)
+}
+
+// GenerateChallengeToken creates a short-lived 2FA challenge token
+func (s *JWTServiceImpl) GenerateChallengeToken(userID uuid.UUID) (string, error) {
+	return s.sign(&jwtClaims{
+		UserID:   userID,
+		TokenUse: tokenUseChallenge,
+	}, challengeExpiry)
+}
+
+// ValidateChallengeToken validates a challenge token and returns its claims
+func (s *JWTServiceImpl) ValidateChallengeToken(tokenString string) (*usecases.JWTClaims, error) {
+	return s.parse(tokenString, tokenUseChallenge)
+}
+
+// ChallengeTokenExpiry returns the lifetime of challenge tokens
+func (s *JWTServiceImpl) ChallengeTokenExpiry() time.Duration {
+	return challengeExpiry
+}
+
+// sign fills in the registered claims and signs the token
+func (s *JWTServiceImpl) sign(claims *jwtClaims, expiry time.Duration) (string, error) {
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Issuer:    s.issuer,
+		Audience:  jwt.ClaimStrings{s.audience},
+		Subject:   claims.UserID.String(),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
+	}
+
+	key := s.keys.current().active
+	token := jwt.NewWithClaims(key.Method, claims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	return token.SignedString(key.SignKey)
+}
+
+// parse validates a token of the expected use and converts its claims. Failures are
+// reported as the domain token error describing the first problem found.
+func (s *JWTServiceImpl) parse(tokenString, tokenUse string) (*usecases.JWTClaims, error) {
 	claims := &jwtClaims{}
 
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return s.jwtKey, nil
-	})
+	_, err := jwt.ParseWithClaims(tokenString, claims, s.keys.current().verificationKey,
+		jwt.WithIssuer(s.issuer),
+		jwt.WithAudience(s.audience),
+		jwt.WithLeeway(s.clockSkew),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, tokenError(err)
+	}
+
+	// Claims the parser does not require on its own
+	if claims.IssuedAt == nil || claims.NotBefore == nil || claims.TokenUse != tokenUse {
+		return nil, domain.ErrTokenInvalidClaims
+	}
+	if claims.UserID == uuid.Nil || claims.Subject != claims.UserID.String() {
+		return nil, domain.ErrTokenInvalidClaims
+	}
+	if tokenUse == tokenUseAccess && claims.SessionID == uuid.Nil {
+		return nil, domain.ErrTokenInvalidClaims
+	}
 
-	if err != nil || !token.Valid {
-		return nil, err
+	// Every token is expected to carry a unique ID so that it can be revoked
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		return nil, domain.ErrTokenInvalidClaims
+	}
+
+	roles := make([]entities.Role, len(claims.Roles))
+	for i, role := range claims.Roles {
+		roles[i] = entities.Role(role)
 	}
 
 	return &usecases.JWTClaims{
-		UserID:   claims.UserID,
-		Username: claims.Username,
+		TokenID:   tokenID,
+		UserID:    claims.UserID,
+		SessionID: claims.SessionID,
+		Username:  claims.Username,
+		Roles:     roles,
+		IssuedAt:  claims.IssuedAt.Time,
+		ExpiresAt: claims.ExpiresAt.Time,
 	}, nil
 }
+
+// tokenError translates a JWT library error into a domain token error
+func tokenError(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrTokenAlgorithm):
+		return domain.ErrTokenAlgorithm
+	case errors.Is(err, domain.ErrTokenSignatureInvalid),
+		errors.Is(err, jwt.ErrTokenSignatureInvalid),
+		errors.Is(err, jwt.ErrTokenUnverifiable):
+		return domain.ErrTokenSignatureInvalid
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return domain.ErrTokenMalformed
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return domain.ErrTokenExpired
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return domain.ErrTokenNotYetValid
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return domain.ErrTokenInvalidIssuer
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return domain.ErrTokenInvalidAudience
+	default:
+		return domain.ErrTokenInvalidClaims
+	}
+}
+
+// GenerateRefreshToken creates a new opaque refresh token
+func (s *JWTServiceImpl) GenerateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashRefreshToken returns the SHA-256 hex digest of a refresh token
+func (s *JWTServiceImpl) HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// AccessTokenExpiry returns the lifetime of access tokens
+func (s *JWTServiceImpl) AccessTokenExpiry() time.Duration {
+	return s.accessExpiry
+}
+
+// RefreshTokenExpiry returns the lifetime of refresh tokens
+func (s *JWTServiceImpl) RefreshTokenExpiry() time.Duration {
+	return s.refreshExpiry
+}
+
+// PublicKeys returns the asymmetric verification keys, the active one first
+func (s *JWTServiceImpl) PublicKeys() []usecases.PublicKey {
+	set := s.keys.current()
+
+	keys := make([]usecases.PublicKey, 0, len(set.keys))
+	for _, key := range set.keys {
+		if key.Public == nil {
+			continue
+		}
+		keys = append(keys, usecases.PublicKey{
+			KeyID:     key.ID,
+			Algorithm: key.Method.Alg(),
+			Key:       key.Public,
+		})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].KeyID == set.active.ID || keys[j].KeyID == set.active.ID {
+			return keys[i].KeyID == set.active.ID
+		}
+		return keys[i].KeyID < keys[j].KeyID
+	})
+
+	return keys
+}
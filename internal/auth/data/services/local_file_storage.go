@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// LocalFileStorage implements the FileStorage interface on a directory of the local
+// filesystem. The files are expected to be served under publicURL.
+type LocalFileStorage struct {
+	dir       string
+	publicURL string
+}
+
+// NewLocalFileStorage creates a storage in dir, creating the directory if needed
+func NewLocalFileStorage(dir, publicURL string) (*LocalFileStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &LocalFileStorage{
+		dir:       dir,
+		publicURL: publicURL,
+	}, nil
+}
+
+// Put writes content to the file of a key. The file is replaced atomically, so readers
+// never see a partial write.
+func (s *LocalFileStorage) Put(key string, content []byte, contentType string) error {
+	filename := s.filename(key)
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".upload-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filename)
+}
+
+// Delete removes the file of a key and the directories it leaves empty
+func (s *LocalFileStorage) Delete(key string) error {
+	filename := s.filename(key)
+	if err := os.Remove(filename); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	// Removing a directory fails once it is not empty, which ends the cleanup
+	root := filepath.Clean(s.dir)
+	for dir := filepath.Dir(filename); dir != root && len(dir) > len(root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// URL returns the public URL of the file of a key
+func (s *LocalFileStorage) URL(key string) string {
+	return s.publicURL + cleanKey(key)
+}
+
+// filename returns the path of the file of a key, which cannot leave the storage directory
+func (s *LocalFileStorage) filename(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(cleanKey(key)))
+}
+
+// cleanKey normalises a key to an absolute slash-separated path without ".." elements
+func cleanKey(key string) string {
+	return path.Clean("/" + key)
+}
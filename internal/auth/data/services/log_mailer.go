@@ -0,0 +1,48 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"musicfy/internal/auth/domain/usecases"
+	"os"
+	"sync"
+	"time"
+)
+
+// LogMailer implements the Mailer interface by writing emails to a writer instead of
+// delivering them. It is meant for development and tests.
+type LogMailer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+// NewLogMailer creates a mailer that writes emails to w
+func NewLogMailer(w io.Writer, from string) *LogMailer {
+	return &LogMailer{
+		w:    w,
+		from: from,
+	}
+}
+
+// NewFileMailer creates a mailer that appends emails to the file at path
+func NewFileMailer(path, from string) (*LogMailer, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLogMailer(file, from), nil
+}
+
+// Send writes an email message to the underlying writer
+func (m *LogMailer) Send(message usecases.EmailMessage) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, err := fmt.Fprintf(m.w,
+		"----- email %s -----\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n----- end of email -----\n",
+		time.Now().Format(time.RFC3339), m.from, message.To, message.Subject, message.Body,
+	)
+	return err
+}
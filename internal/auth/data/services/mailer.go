@@ -0,0 +1,32 @@
+package services
+
+import (
+	"log"
+	"musicfy/internal/auth/domain/usecases"
+	"musicfy/internal/config"
+	"os"
+)
+
+// NewMailer creates the mailer selected by the MAILER_DRIVER setting
+func NewMailer() usecases.Mailer {
+	cfg := config.AppConfig.MailerConfig
+
+	switch cfg.Driver {
+	case "smtp":
+		if cfg.SMTPHost == "" {
+			log.Fatalf("SMTP mailer requires SMTP_HOST to be set")
+		}
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.From)
+	case "file":
+		mailer, err := NewFileMailer(cfg.FilePath, cfg.From)
+		if err != nil {
+			log.Fatalf("Failed to open mail file: %v", err)
+		}
+		return mailer
+	case "log", "":
+		return NewLogMailer(os.Stdout, cfg.From)
+	default:
+		log.Fatalf("Unknown mailer driver: %s", cfg.Driver)
+		return nil
+	}
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"musicfy/internal/auth/domain/usecases"
+	"musicfy/internal/config"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Supported password hashing algorithms
+const (
+	PasswordAlgorithmBcrypt   = "bcrypt"
+	PasswordAlgorithmArgon2id = "argon2id"
+)
+
+// errUnknownHashFormat is returned for hashes that no supported algorithm produced
+var errUnknownHashFormat = errors.New("unknown password hash format")
+
+// passwordAlgorithm is a PasswordHasher that can also recognise its own hashes
+type passwordAlgorithm interface {
+	usecases.PasswordHasher
+	Handles(hash string) bool
+}
+
+// PasswordHasherImpl hashes new passwords with the configured algorithm and verifies hashes
+// of every supported algorithm, so that accounts can be migrated as users sign in
+type PasswordHasherImpl struct {
+	preferred  passwordAlgorithm
+	algorithms []passwordAlgorithm
+}
+
+// NewPasswordHasher creates the password hasher configured for the environment
+func NewPasswordHasher() *PasswordHasherImpl {
+	cfg := config.AppConfig.PasswordConfig
+
+	bcryptHasher := NewBcryptHasher(cfg.BcryptCost)
+	argon2idHasher := NewArgon2idHasher(cfg.Argon2Memory, cfg.Argon2Iterations, cfg.Argon2Parallelism, cfg.Argon2MemoryBudget)
+
+	hasher := &PasswordHasherImpl{algorithms: []passwordAlgorithm{argon2idHasher, bcryptHasher}}
+	switch cfg.Algorithm {
+	case PasswordAlgorithmBcrypt:
+		hasher.preferred = bcryptHasher
+	case PasswordAlgorithmArgon2id:
+		hasher.preferred = argon2idHasher
+	default:
+		log.Fatalf("Unsupported PASSWORD_HASH_ALGORITHM %q", cfg.Algorithm)
+	}
+
+	return hasher
+}
+
+// Hash hashes a password with the configured algorithm
+func (h *PasswordHasherImpl) Hash(password string) (string, error) {
+	return h.preferred.Hash(password)
+}
+
+// Verify checks a password against a hash of any supported algorithm
+func (h *PasswordHasherImpl) Verify(hash, password string) (bool, error) {
+	for _, algorithm := range h.algorithms {
+		if algorithm.Handles(hash) {
+			return algorithm.Verify(hash, password)
+		}
+	}
+	return false, errUnknownHashFormat
+}
+
+// NeedsRehash reports whether the hash differs from what Hash would produce now
+func (h *PasswordHasherImpl) NeedsRehash(hash string) bool {
+	return !h.preferred.Handles(hash) || h.preferred.NeedsRehash(hash)
+}
+
+// BcryptMaxPasswordBytes is the longest password bcrypt can hash
+const BcryptMaxPasswordBytes = 72
+
+// BcryptHasher hashes passwords with bcrypt
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher creates a bcrypt hasher; out-of-range costs fall back to bcrypt.DefaultCost
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{cost: cost}
+}
+
+// Handles reports whether the hash is a bcrypt hash
+func (h *BcryptHasher) Handles(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// Hash hashes a password with bcrypt
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify checks a password against a bcrypt hash. Passwords too long for bcrypt cannot
+// match, since they could never have been hashed.
+func (h *BcryptHasher) Verify(hash, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) || errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// NeedsRehash reports whether the hash was made with another cost
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.cost
+}
+
+// Argon2id output sizes
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// Argon2idHasher hashes passwords with Argon2id, encoded in the PHC string format
+// $argon2id$v=19$m=<KiB>,t=<iterations>,p=<parallelism>$<salt>$<key>
+type Argon2idHasher struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	budget      *memoryBudget
+}
+
+// argon2idParams are the parameters read back from an encoded hash
+type argon2idParams struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// NewArgon2idHasher creates an Argon2id hasher; memory is in KiB. Concurrent hashes wait
+// until they fit into memoryBudget KiB together.
+func NewArgon2idHasher(memory, iterations, parallelism, memoryBudget int) *Argon2idHasher {
+	if memory < 8*parallelism || iterations < 1 || parallelism < 1 || parallelism > 255 {
+		log.Fatalf("Invalid Argon2id parameters m=%d t=%d p=%d", memory, iterations, parallelism)
+	}
+	if memoryBudget < memory {
+		log.Fatalf("Argon2id memory budget of %d KiB cannot fit a single hash of %d KiB", memoryBudget, memory)
+	}
+	return &Argon2idHasher{
+		memory:      uint32(memory),
+		iterations:  uint32(iterations),
+		parallelism: uint8(parallelism),
+		budget:      newMemoryBudget(uint64(memoryBudget)),
+	}
+}
+
+// Handles reports whether the hash is an Argon2id hash
+func (h *Argon2idHasher) Handles(hash string) bool {
+	return strings.HasPrefix(hash, "$argon2id$")
+}
+
+// Hash hashes a password with Argon2id and a random salt
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	if err := h.budget.acquire(uint64(h.memory)); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.iterations, h.memory, h.parallelism, argon2KeyLength)
+	h.budget.release(uint64(h.memory))
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.memory, h.iterations, h.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify checks a password against an Argon2id hash using the parameters stored in it
+func (h *Argon2idHasher) Verify(hash, password string) (bool, error) {
+	params, err := decodeArgon2id(hash)
+	if err != nil {
+		return false, err
+	}
+
+	if err := h.budget.acquire(uint64(params.memory)); err != nil {
+		return false, err
+	}
+	key := argon2.IDKey([]byte(password), params.salt, params.iterations, params.memory, params.parallelism, uint32(len(params.key)))
+	h.budget.release(uint64(params.memory))
+
+	return subtle.ConstantTimeCompare(key, params.key) == 1, nil
+}
+
+// NeedsRehash reports whether the hash was made with other parameters
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+	return params.memory != h.memory || params.iterations != h.iterations ||
+		params.parallelism != h.parallelism || len(params.key) != argon2KeyLength
+}
+
+// decodeArgon2id parses an Argon2id hash in the PHC string format
+func decodeArgon2id(hash string) (*argon2idParams, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, errUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, fmt.Errorf("unsupported argon2 version %q", parts[2])
+	}
+
+	params := &argon2idParams{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism); err != nil {
+		return nil, fmt.Errorf("invalid argon2 parameters: %w", err)
+	}
+	if params.iterations < 1 || params.parallelism < 1 || params.memory < 8*uint32(params.parallelism) {
+		return nil, fmt.Errorf("invalid argon2 parameters %q", parts[3])
+	}
+
+	var err error
+	if params.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, fmt.Errorf("invalid argon2 salt: %w", err)
+	}
+	if params.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(params.key) == 0 {
+		return nil, errors.New("invalid argon2 key")
+	}
+
+	return params, nil
+}
+
+// errArgon2MemoryBudget is returned for hashes that need more memory than the whole budget
+var errArgon2MemoryBudget = errors.New("argon2 hash exceeds the memory budget")
+
+// memoryBudget bounds the memory, in KiB, held by concurrent Argon2id computations. Every
+// computation reserves its memory and waits until enough is free.
+type memoryBudget struct {
+	mu    sync.Mutex
+	freed *sync.Cond
+	total uint64
+	free  uint64
+}
+
+// newMemoryBudget creates a budget of total KiB
+func newMemoryBudget(total uint64) *memoryBudget {
+	b := &memoryBudget{total: total, free: total}
+	b.freed = sync.NewCond(&b.mu)
+	return b
+}
+
+// acquire reserves kib KiB, waiting for other computations to release memory if needed
+func (b *memoryBudget) acquire(kib uint64) error {
+	if kib > b.total {
+		return errArgon2MemoryBudget
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.free < kib {
+		b.freed.Wait()
+	}
+	b.free -= kib
+	return nil
+}
+
+// release returns memory reserved with acquire
+func (b *memoryBudget) release(kib uint64) {
+	b.mu.Lock()
+	b.free += kib
+	b.mu.Unlock()
+	b.freed.Broadcast()
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Cheap parameters keep the tests fast
+const (
+	testArgon2Memory      = 64
+	testArgon2Iterations  = 1
+	testArgon2Parallelism = 1
+	testArgon2Budget      = 1024
+)
+
+func newTestPasswordHasher(preferred string) *PasswordHasherImpl {
+	bcryptHasher := NewBcryptHasher(bcrypt.MinCost)
+	argon2idHasher := NewArgon2idHasher(testArgon2Memory, testArgon2Iterations, testArgon2Parallelism, testArgon2Budget)
+
+	hasher := &PasswordHasherImpl{algorithms: []passwordAlgorithm{argon2idHasher, bcryptHasher}}
+	if preferred == PasswordAlgorithmBcrypt {
+		hasher.preferred = bcryptHasher
+	} else {
+		hasher.preferred = argon2idHasher
+	}
+	return hasher
+}
+
+func TestArgon2idHashRoundTrip(t *testing.T) {
+	h := NewArgon2idHasher(testArgon2Memory, testArgon2Iterations, testArgon2Parallelism, testArgon2Budget)
+
+	hash, err := h.Hash("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("unexpected hash format %q", hash)
+	}
+
+	params, err := decodeArgon2id(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.memory != testArgon2Memory || params.iterations != testArgon2Iterations || params.parallelism != testArgon2Parallelism {
+		t.Errorf("decoded parameters m=%d t=%d p=%d", params.memory, params.iterations, params.parallelism)
+	}
+	if len(params.salt) != argon2SaltLength || len(params.key) != argon2KeyLength {
+		t.Errorf("decoded %d salt and %d key bytes", len(params.salt), len(params.key))
+	}
+
+	if ok, err := h.Verify(hash, "correct horse battery staple"); !ok || err != nil {
+		t.Errorf("Verify(correct password) = %v, %v", ok, err)
+	}
+	if ok, err := h.Verify(hash, "correct horse battery stapler"); ok || err != nil {
+		t.Errorf("Verify(wrong password) = %v, %v", ok, err)
+	}
+
+	other, err := h.Hash("correct horse battery staple")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == hash {
+		t.Error("two hashes of the same password share a salt")
+	}
+}
+
+func TestArgon2idVerifyUsesStoredParameters(t *testing.T) {
+	// Hashes made before the parameters were raised must still verify
+	h := NewArgon2idHasher(128, 2, 1, testArgon2Budget)
+	hash, err := NewArgon2idHasher(testArgon2Memory, testArgon2Iterations, testArgon2Parallelism, testArgon2Budget).Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := h.Verify(hash, "secret"); !ok || err != nil {
+		t.Errorf("Verify() with other configured parameters = %v, %v", ok, err)
+	}
+}
+
+func TestDecodeArgon2idMalformed(t *testing.T) {
+	valid := "$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHRzYWx0c2FsdA$a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U"
+	if _, err := decodeArgon2id(valid); err != nil {
+		t.Fatalf("valid hash rejected: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{name: "empty", hash: ""},
+		{name: "other algorithm", hash: "$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5"},
+		{name: "missing field", hash: "$argon2id$v=19$m=64,t=1,p=1$c2FsdA"},
+		{name: "extra field", hash: valid + "$extra"},
+		{name: "other version", hash: "$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5"},
+		{name: "garbled parameters", hash: "$argon2id$v=19$m=x,t=1,p=1$c2FsdA$a2V5"},
+		{name: "zero iterations", hash: "$argon2id$v=19$m=64,t=0,p=1$c2FsdA$a2V5"},
+		{name: "zero parallelism", hash: "$argon2id$v=19$m=64,t=1,p=0$c2FsdA$a2V5"},
+		{name: "too little memory for the lanes", hash: "$argon2id$v=19$m=8,t=1,p=2$c2FsdA$a2V5"},
+		{name: "padded salt", hash: "$argon2id$v=19$m=64,t=1,p=1$c2FsdA==$a2V5"},
+		{name: "invalid key encoding", hash: "$argon2id$v=19$m=64,t=1,p=1$c2FsdA$!!!"},
+		{name: "empty key", hash: "$argon2id$v=19$m=64,t=1,p=1$c2FsdA$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeArgon2id(tt.hash); err == nil {
+				t.Errorf("decodeArgon2id(%q) succeeded", tt.hash)
+			}
+		})
+	}
+}
+
+func TestArgon2idVerifyOverBudget(t *testing.T) {
+	h := NewArgon2idHasher(testArgon2Memory, testArgon2Iterations, testArgon2Parallelism, testArgon2Budget)
+
+	if _, err := h.Verify("$argon2id$v=19$m=1048576,t=1,p=1$c2FsdA$a2V5", "secret"); err == nil {
+		t.Error("Verify() accepted a hash needing more memory than the budget")
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	argon2Hash, err := NewArgon2idHasher(testArgon2Memory, testArgon2Iterations, testArgon2Parallelism, testArgon2Budget).Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcryptHash, err := NewBcryptHasher(bcrypt.MinCost).Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		hasher *PasswordHasherImpl
+		hash   string
+		want   bool
+	}{
+		{name: "current argon2id parameters", hasher: newTestPasswordHasher(PasswordAlgorithmArgon2id), hash: argon2Hash, want: false},
+		{name: "argon2id memory raised", hasher: &PasswordHasherImpl{preferred: NewArgon2idHasher(128, 1, 1, testArgon2Budget)}, hash: argon2Hash, want: true},
+		{name: "argon2id iterations raised", hasher: &PasswordHasherImpl{preferred: NewArgon2idHasher(64, 2, 1, testArgon2Budget)}, hash: argon2Hash, want: true},
+		{name: "argon2id parallelism raised", hasher: &PasswordHasherImpl{preferred: NewArgon2idHasher(64, 1, 2, testArgon2Budget)}, hash: argon2Hash, want: true},
+		{name: "argon2id key length changed", hasher: newTestPasswordHasher(PasswordAlgorithmArgon2id), hash: "$argon2id$v=19$m=64,t=1,p=1$c2FsdA$a2V5", want: true},
+		{name: "bcrypt hash with argon2id preferred", hasher: newTestPasswordHasher(PasswordAlgorithmArgon2id), hash: bcryptHash, want: true},
+		{name: "current bcrypt cost", hasher: newTestPasswordHasher(PasswordAlgorithmBcrypt), hash: bcryptHash, want: false},
+		{name: "bcrypt cost raised", hasher: &PasswordHasherImpl{preferred: NewBcryptHasher(bcrypt.MinCost + 1)}, hash: bcryptHash, want: true},
+		{name: "argon2id hash with bcrypt preferred", hasher: newTestPasswordHasher(PasswordAlgorithmBcrypt), hash: argon2Hash, want: true},
+		{name: "malformed hash", hasher: newTestPasswordHasher(PasswordAlgorithmArgon2id), hash: "$argon2id$garbage", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hasher.NeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyAcrossAlgorithms(t *testing.T) {
+	hasher := newTestPasswordHasher(PasswordAlgorithmArgon2id)
+
+	// A legacy bcrypt hash still verifies and is flagged for migration
+	legacy, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := hasher.Verify(string(legacy), "secret"); !ok || err != nil {
+		t.Fatalf("Verify(bcrypt hash) = %v, %v", ok, err)
+	}
+	if !hasher.NeedsRehash(string(legacy)) {
+		t.Fatal("bcrypt hash not flagged for rehashing")
+	}
+
+	// The rehash is an Argon2id hash that verifies the same password
+	rehashed, err := hasher.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(rehashed, "$argon2id$") {
+		t.Errorf("rehash %q is not an Argon2id hash", rehashed)
+	}
+	if ok, err := hasher.Verify(rehashed, "secret"); !ok || err != nil {
+		t.Errorf("Verify(rehashed) = %v, %v", ok, err)
+	}
+	if hasher.NeedsRehash(rehashed) {
+		t.Error("fresh hash flagged for rehashing")
+	}
+
+	// Every $2 variant is recognised
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		variant := prefix + string(legacy)[4:]
+		if ok, err := hasher.Verify(variant, "wrong"); ok || err != nil {
+			t.Errorf("Verify(%s hash, wrong password) = %v, %v", prefix, ok, err)
+		}
+	}
+
+	// Unknown formats are errors rather than mismatches
+	if _, err := hasher.Verify("$1$md5crypt$hash", "secret"); err != errUnknownHashFormat {
+		t.Errorf("Verify(unknown format) error = %v, want %v", err, errUnknownHashFormat)
+	}
+}
+
+func TestBcryptVerifyLongPassword(t *testing.T) {
+	h := NewBcryptHasher(bcrypt.MinCost)
+	hash, err := h.Hash("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	long := strings.Repeat("a", BcryptMaxPasswordBytes+1)
+	if ok, err := h.Verify(hash, long); ok || err != nil {
+		t.Errorf("Verify(%d byte password) = %v, %v; want a mismatch", len(long), ok, err)
+	}
+}
+
+func TestMemoryBudgetBoundsConcurrency(t *testing.T) {
+	budget := newMemoryBudget(100)
+
+	if err := budget.acquire(101); err != errArgon2MemoryBudget {
+		t.Fatalf("acquire(101) error = %v, want %v", err, errArgon2MemoryBudget)
+	}
+
+	// Two reservations of 60 cannot be held at once
+	if err := budget.acquire(60); err != nil {
+		t.Fatal(err)
+	}
+	acquired := make(chan struct{})
+	go func() {
+		budget.acquire(60)
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second reservation did not wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	budget.release(60)
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("second reservation not granted after release")
+	}
+	budget.release(60)
+
+	// Everything is free again
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			budget.acquire(10)
+		}()
+	}
+	wg.Wait()
+	if budget.free != 0 {
+		t.Errorf("free = %d after reserving the whole budget, want 0", budget.free)
+	}
+}
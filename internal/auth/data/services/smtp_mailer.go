@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"musicfy/internal/auth/domain/usecases"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPMailer implements the Mailer interface by sending emails through an SMTP server
+type SMTPMailer struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPMailer creates a new SMTP mailer
+func NewSMTPMailer(host string, port int, username, password, from string) *SMTPMailer {
+	return &SMTPMailer{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// Send delivers an email message; STARTTLS is used whenever the server offers it
+func (m *SMTPMailer) Send(message usecases.EmailMessage) error {
+	from, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	data, err := buildMessage(from.String(), to.String(), message)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	return smtp.SendMail(m.addr, auth, from.Address, []string{to.Address}, data)
+}
+
+// buildMessage renders an email message with its headers
+func buildMessage(from, to string, message usecases.EmailMessage) ([]byte, error) {
+	if strings.ContainsAny(message.Subject, "\r\n") {
+		return nil, errors.New("email subject must not contain line breaks")
+	}
+
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + message.Subject + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the shared secret of the RFC 4226 and RFC 6238 (SHA-1) test vectors
+var rfcSecret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestHOTPRFC4226Vectors(t *testing.T) {
+	// RFC 4226, Appendix D
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	for counter, code := range want {
+		if got := hotp([]byte("12345678901234567890"), int64(counter)); got != code {
+			t.Errorf("hotp(counter %d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+func TestValidateCodeRFC6238Vectors(t *testing.T) {
+	// RFC 6238, Appendix B (SHA-1); the 6 digit codes are the last 6 of the 8 digit ones
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		step, ok := validateCodeAt(rfcSecret, tt.code, time.Unix(tt.unix, 0))
+		if !ok {
+			t.Errorf("code %s rejected at %d", tt.code, tt.unix)
+			continue
+		}
+		if want := tt.unix / totpPeriod; step != want {
+			t.Errorf("code %s at %d matched step %d, want %d", tt.code, tt.unix, step, want)
+		}
+	}
+}
+
+func TestValidateCodeSkewWindow(t *testing.T) {
+	key := []byte("12345678901234567890")
+	now := time.Unix(1234567890, 0)
+	current := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name   string
+		offset int64
+		ok     bool
+	}{
+		{name: "current step", offset: 0, ok: true},
+		{name: "previous step", offset: -1, ok: true},
+		{name: "next step", offset: 1, ok: true},
+		{name: "two steps behind", offset: -2, ok: false},
+		{name: "two steps ahead", offset: 2, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := validateCodeAt(rfcSecret, hotp(key, current+tt.offset), now)
+			if ok != tt.ok {
+				t.Fatalf("validateCodeAt() ok = %v, want %v", ok, tt.ok)
+			}
+			if ok && step != current+tt.offset {
+				t.Errorf("validateCodeAt() step = %d, want %d", step, current+tt.offset)
+			}
+		})
+	}
+}
+
+func TestValidateCodeMalformedInput(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		ok     bool
+	}{
+		{name: "lowercase secret with spaces", secret: " " + strings.ToLower(rfcSecret) + " ", code: "287082", ok: true},
+		{name: "code too short", secret: rfcSecret, code: "28708", ok: false},
+		{name: "code too long", secret: rfcSecret, code: "2870820", ok: false},
+		{name: "eight digit code", secret: rfcSecret, code: "94287082", ok: false},
+		{name: "empty code", secret: rfcSecret, code: "", ok: false},
+		{name: "invalid secret", secret: "not base32!", code: "287082", ok: false},
+		{name: "wrong code", secret: rfcSecret, code: "123456", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := validateCodeAt(tt.secret, tt.code, now); ok != tt.ok {
+				t.Errorf("validateCodeAt() ok = %v, want %v", ok, tt.ok)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	s := &TOTPServiceImpl{issuer: "Musicfy"}
+
+	secret, err := s.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret %q is not unpadded base32: %v", secret, err)
+	}
+	if len(key) != totpSecretSize {
+		t.Errorf("secret has %d bytes, want %d", len(key), totpSecretSize)
+	}
+
+	other, err := s.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == secret {
+		t.Error("two generated secrets are equal")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	s := &TOTPServiceImpl{issuer: "Musicfy"}
+
+	uri, err := url.Parse(s.ProvisioningURI(rfcSecret, "john@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Musicfy:john@example.com" {
+		t.Errorf("unexpected URI %s", uri)
+	}
+
+	query := uri.Query()
+	want := map[string]string{"secret": rfcSecret, "issuer": "Musicfy", "algorithm": "SHA1", "digits": "6", "period": "30"}
+	for name, value := range want {
+		if got := query.Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthEventType identifies what happened in an authentication event
+type AuthEventType string
+
+// Authentication event types
+const (
+	AuthEventRegistered                 AuthEventType = "registered"
+	AuthEventLoginSucceeded             AuthEventType = "login_succeeded"
+	AuthEventLoginFailed                AuthEventType = "login_failed"
+	AuthEventLogout                     AuthEventType = "logout"
+	AuthEventLogoutAll                  AuthEventType = "logout_all"
+	AuthEventSessionRevoked             AuthEventType = "session_revoked"
+	AuthEventRefreshTokenReused         AuthEventType = "refresh_token_reused"
+	AuthEventPasswordChanged            AuthEventType = "password_changed"
+	AuthEventPasswordReset              AuthEventType = "password_reset"
+	AuthEventUsernameChanged            AuthEventType = "username_changed"
+	AuthEventEmailChanged               AuthEventType = "email_changed"
+	AuthEventPersonalAccessTokenCreated AuthEventType = "personal_access_token_created"
+	AuthEventPersonalAccessTokenRevoked AuthEventType = "personal_access_token_revoked"
+	AuthEventTwoFactorEnabled           AuthEventType = "two_factor_enabled"
+	AuthEventTwoFactorDisabled          AuthEventType = "two_factor_disabled"
+	AuthEventRecoveryCodesRegenerated   AuthEventType = "recovery_codes_regenerated"
+	AuthEventAccountSuspended           AuthEventType = "account_suspended"
+	AuthEventAccountUnsuspended         AuthEventType = "account_unsuspended"
+	AuthEventAccountUnlocked            AuthEventType = "account_unlocked"
+	AuthEventPasswordResetForced        AuthEventType = "password_reset_forced"
+	AuthEventAccountDeleted             AuthEventType = "account_deleted"
+	AuthEventInviteCreated              AuthEventType = "invite_created"
+)
+
+// AuthEvent is an entry of the authentication audit log. UserID is nil for events that
+// cannot be tied to an account, such as a login attempt for an unknown username.
+type AuthEvent struct {
+	ID        uuid.UUID
+	UserID    *uuid.UUID
+	Type      AuthEventType
+	IPAddress string
+	UserAgent string
+	Metadata  map[string]string
+	CreatedAt time.Time
+}
+
+// NewAuthEvent creates a new authentication event
+func NewAuthEvent(userID *uuid.UUID, eventType AuthEventType, ipAddress, userAgent string, metadata map[string]string) *AuthEvent {
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	return &AuthEvent{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Type:      eventType,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Metadata:  metadata,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,36 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerificationToken represents a single-use token that confirms ownership of an email address
+type EmailVerificationToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Email     string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+// NewEmailVerificationToken creates a new verification token for the given address
+func NewEmailVerificationToken(userID uuid.UUID, email, tokenHash string, ttl time.Duration) *EmailVerificationToken {
+	now := time.Now()
+	return &EmailVerificationToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Email:     email,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsUsable reports whether the token is neither used nor expired
+func (t *EmailVerificationToken) IsUsable() bool {
+	return t.UsedAt == nil && time.Now().Before(t.ExpiresAt)
+}
@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invite represents a registration code minted by an admin. It can be redeemed MaxUses
+// times until it expires.
+type Invite struct {
+	ID         uuid.UUID
+	CodeHash   string
+	CodePrefix string
+	Note       string
+	MaxUses    int
+	UseCount   int
+	ExpiresAt  *time.Time
+	CreatedBy  *uuid.UUID
+	CreatedAt  time.Time
+}
+
+// InviteRedemption records the registration of a user with an invite
+type InviteRedemption struct {
+	ID         uuid.UUID
+	InviteID   uuid.UUID
+	UserID     uuid.UUID
+	RedeemedAt time.Time
+}
+
+// NewInvite creates a new invite; expiresAt may be nil
+func NewInvite(codeHash, codePrefix, note string, maxUses int, expiresAt *time.Time, createdBy uuid.UUID) *Invite {
+	return &Invite{
+		ID:         uuid.New(),
+		CodeHash:   codeHash,
+		CodePrefix: codePrefix,
+		Note:       note,
+		MaxUses:    maxUses,
+		ExpiresAt:  expiresAt,
+		CreatedBy:  &createdBy,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// IsExpired reports whether the invite has an expiry time that has passed
+func (i *Invite) IsExpired() bool {
+	return i.ExpiresAt != nil && time.Now().After(*i.ExpiresAt)
+}
+
+// IsUsable reports whether the invite has uses left and has not expired
+func (i *Invite) IsUsable() bool {
+	return i.UseCount < i.MaxUses && !i.IsExpired()
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginThrottle tracks failed login attempts for an account or a client IP
+type LoginThrottle struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+// AccountThrottleKey returns the throttle key for a user account
+func AccountThrottleKey(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+// IPThrottleKey returns the throttle key for a client IP address
+func IPThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+// MagicLinkThrottleKey returns the throttle key for magic link requests from a client IP
+func MagicLinkThrottleKey(ip string) string {
+	return "magic_link_ip:" + ip
+}
+
+// IsLocked reports whether login attempts are currently blocked
+func (t *LoginThrottle) IsLocked() bool {
+	return t.LockedUntil != nil && time.Now().Before(*t.LockedUntil)
+}
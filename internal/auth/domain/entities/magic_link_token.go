@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MagicLinkToken represents a single-use emailed login link. It is bound to the device that
+// requested it: the link only works together with the device binding that was returned
+// to that device.
+type MagicLinkToken struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	TokenHash  string
+	DeviceHash string
+	IPAddress  string
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	UsedAt     *time.Time
+}
+
+// NewMagicLinkToken creates a new magic link token
+func NewMagicLinkToken(userID uuid.UUID, tokenHash, deviceHash, ipAddress string, ttl time.Duration) *MagicLinkToken {
+	now := time.Now()
+	return &MagicLinkToken{
+		ID:         uuid.New(),
+		UserID:     userID,
+		TokenHash:  tokenHash,
+		DeviceHash: deviceHash,
+		IPAddress:  ipAddress,
+		ExpiresAt:  now.Add(ttl),
+		CreatedAt:  now,
+	}
+}
+
+// IsUsable reports whether the token is neither used nor expired
+func (t *MagicLinkToken) IsUsable() bool {
+	return t.UsedAt == nil && time.Now().Before(t.ExpiresAt)
+}
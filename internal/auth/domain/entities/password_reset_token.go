@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetToken represents a single-use token that allows a user to choose a new password
+type PasswordResetToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+// NewPasswordResetToken creates a new password reset token
+func NewPasswordResetToken(userID uuid.UUID, tokenHash string, ttl time.Duration) *PasswordResetToken {
+	now := time.Now()
+	return &PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsUsable reports whether the token is neither used nor expired
+func (t *PasswordResetToken) IsUsable() bool {
+	return t.UsedAt == nil && time.Now().Before(t.ExpiresAt)
+}
@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalAccessTokenPrefix starts every personal access token, which tells them apart from JWTs
+const PersonalAccessTokenPrefix = "mfy_pat_"
+
+// PersonalAccessToken represents a long-lived credential for scripts and integrations.
+// It grants only the permissions listed in Scopes, and only while the owner's roles still
+// grant them.
+type PersonalAccessToken struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	Prefix     string
+	TokenHash  string
+	Scopes     []Permission
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+	RevokedAt  *time.Time
+}
+
+// NewPersonalAccessToken creates a new personal access token; expiresAt may be nil
+func NewPersonalAccessToken(userID uuid.UUID, name, prefix, tokenHash string, scopes []Permission, expiresAt *time.Time) *PersonalAccessToken {
+	return &PersonalAccessToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		TokenHash: tokenHash,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsExpired reports whether the token has an expiry time that has passed
+func (t *PersonalAccessToken) IsExpired() bool {
+	return t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt)
+}
+
+// IsRevoked reports whether the token has been revoked
+func (t *PersonalAccessToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken represents a long-lived credential used to obtain new access tokens.
+// Tokens issued from the same login share a FamilyID so that the whole chain can be
+// revoked when a rotated token is presented again.
+type RefreshToken struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	FamilyID   uuid.UUID
+	TokenHash  string
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy *uuid.UUID
+}
+
+// NewRefreshToken creates a new refresh token belonging to the given family
+func NewRefreshToken(userID, familyID uuid.UUID, tokenHash string, ttl time.Duration) *RefreshToken {
+	now := time.Now()
+	return &RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsExpired reports whether the token is past its expiry time
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+// IsRevoked reports whether the token has been revoked or rotated
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// IsRotated reports whether the token has been exchanged for a successor
+func (t *RefreshToken) IsRotated() bool {
+	return t.ReplacedBy != nil
+}
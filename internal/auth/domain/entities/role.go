@@ -0,0 +1,88 @@
+package entities
+
+// Role represents a set of capabilities granted to a user
+type Role string
+
+const (
+	// RoleListener can stream music and manage their own playlists
+	RoleListener Role = "listener"
+	// RoleArtist can additionally publish tracks
+	RoleArtist Role = "artist"
+	// RoleCurator can additionally curate public playlists
+	RoleCurator Role = "curator"
+	// RoleAdmin can do everything, including managing users
+	RoleAdmin Role = "admin"
+)
+
+// Permission represents a single capability checked by protected endpoints
+type Permission string
+
+const (
+	PermissionStreamMusic     Permission = "music:stream"
+	PermissionManagePlaylists Permission = "playlists:manage"
+	PermissionUploadTracks    Permission = "tracks:upload"
+	PermissionCuratePlaylists Permission = "playlists:curate"
+	PermissionManageUsers     Permission = "users:manage"
+)
+
+// rolePermissions maps every role to the permissions it grants
+var rolePermissions = map[Role][]Permission{
+	RoleListener: {PermissionStreamMusic, PermissionManagePlaylists},
+	RoleArtist:   {PermissionStreamMusic, PermissionManagePlaylists, PermissionUploadTracks},
+	RoleCurator:  {PermissionStreamMusic, PermissionManagePlaylists, PermissionCuratePlaylists},
+	RoleAdmin: {
+		PermissionStreamMusic,
+		PermissionManagePlaylists,
+		PermissionUploadTracks,
+		PermissionCuratePlaylists,
+		PermissionManageUsers,
+	},
+}
+
+// IsValid reports whether the role is a known role
+func (r Role) IsValid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// IsValid reports whether the permission is granted by any role
+func (p Permission) IsValid() bool {
+	for role := range rolePermissions {
+		if role.HasPermission(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether the role grants the permission
+func (r Role) HasPermission(permission Permission) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAnyRole reports whether roles contains at least one of the wanted roles
+func HasAnyRole(roles []Role, wanted ...Role) bool {
+	for _, role := range roles {
+		for _, w := range wanted {
+			if role == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RolesHavePermission reports whether any of the roles grants the permission
+func RolesHavePermission(roles []Role, permission Permission) bool {
+	for _, role := range roles {
+		if role.HasPermission(permission) {
+			return true
+		}
+	}
+	return false
+}
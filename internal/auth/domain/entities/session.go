@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session represents one sign-in on a device. Its ID is shared with the refresh token
+// family issued for it and embedded in every access token, so revoking a session signs
+// the device out.
+type Session struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+}
+
+// NewSession creates a new session lasting as long as the given refresh token lifetime
+func NewSession(userID uuid.UUID, deviceName, userAgent, ipAddress string, ttl time.Duration) *Session {
+	now := time.Now()
+	return &Session{
+		ID:         uuid.New(),
+		UserID:     userID,
+		DeviceName: deviceName,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+}
+
+// IsActive reports whether the session has neither been revoked nor expired
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
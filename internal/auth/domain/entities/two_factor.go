@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactor represents a user's TOTP enrollment
+type TwoFactor struct {
+	UserID       uuid.UUID
+	Secret       string
+	ConfirmedAt  *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+// NewTwoFactor creates a new pending TOTP enrollment
+func NewTwoFactor(userID uuid.UUID, secret string) *TwoFactor {
+	return &TwoFactor{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	}
+}
+
+// IsEnabled reports whether the enrollment has been confirmed with a first code
+func (t *TwoFactor) IsEnabled() bool {
+	return t.ConfirmedAt != nil
+}
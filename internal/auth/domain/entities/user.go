@@ -1,11 +1,38 @@
 package entities
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
 )
 
+// UserStatus tells whether a user may sign in
+type UserStatus string
+
+// User statuses
+const (
+	UserStatusActive                UserStatus = "active"
+	UserStatusSuspended             UserStatus = "suspended"
+	UserStatusPasswordResetRequired UserStatus = "password_reset_required"
+)
+
+// IsValid reports whether the status is one of the defined statuses
+func (s UserStatus) IsValid() bool {
+	switch s {
+	case UserStatusActive, UserStatusSuspended, UserStatusPasswordResetRequired:
+		return true
+	}
+	return false
+}
+
+// AdultAge is the age from which a user is no longer a minor
+const AdultAge = 18
+
+// DateOfBirthLayout is the format of dates of birth in requests and responses
+const DateOfBirthLayout = "2006-01-02"
+
 // User represents the core user entity in the domain
 type User struct {
 	ID           uuid.UUID
@@ -13,29 +40,89 @@ type User struct {
 	LastName     string
 	Username     string
 	Email        string
-	Age          int
+	DateOfBirth  time.Time
 	PasswordHash string
+	Roles        []Role
+	Status       UserStatus
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
+
+	// AvatarKey locates the avatar renditions in file storage; empty without an avatar
+	AvatarKey string
+
+	// EmailVerifiedAt is set once the user confirms their email address
+	EmailVerifiedAt *time.Time
+
+	// TokensValidAfter invalidates every token issued before it when set
+	TokensValidAfter *time.Time
 }
 
 // NewUser creates a new user with default values
-func NewUser(firstName, lastName, username, email string, age int, passwordHash string) *User {
+func NewUser(firstName, lastName, username, email string, dateOfBirth time.Time, passwordHash string) *User {
 	now := time.Now()
 	return &User{
 		ID:           uuid.New(),
 		FirstName:    firstName,
 		LastName:     lastName,
-		Username:     username,
-		Email:        email,
-		Age:          age,
+		Username:     NormalizeUsername(username),
+		Email:        NormalizeEmail(email),
+		DateOfBirth:  dateOfBirth,
 		PasswordHash: passwordHash,
+		Roles:        []Role{RoleListener},
+		Status:       UserStatusActive,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
 }
 
+// NormalizeUsername returns the canonical form of a username. NFKC normalization folds
+// look-alike forms such as full-width letters; case is kept for display, since usernames
+// are compared case-insensitively.
+func NormalizeUsername(username string) string {
+	return norm.NFKC.String(strings.TrimSpace(username))
+}
+
+// IsValidUsername reports whether a normalized username can be told apart from an email
+// address, which it cannot when it contains an @
+func IsValidUsername(username string) bool {
+	return !strings.Contains(username, "@")
+}
+
+// NormalizeEmail returns the canonical, lower-case form of an email address
+func NormalizeEmail(email string) string {
+	return strings.ToLower(norm.NFKC.String(strings.TrimSpace(email)))
+}
+
+// AgeOn returns the age in whole years, on the given day, of someone born on dateOfBirth
+func AgeOn(dateOfBirth, day time.Time) int {
+	age := day.Year() - dateOfBirth.Year()
+	if day.Month() < dateOfBirth.Month() || (day.Month() == dateOfBirth.Month() && day.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
+
+// Age returns the current age of the user
+func (u *User) Age() int {
+	return AgeOn(u.DateOfBirth, time.Now().UTC())
+}
+
+// IsMinor reports whether the user is younger than AdultAge, for example to filter content
+func (u *User) IsMinor() bool {
+	return u.Age() < AdultAge
+}
+
 // FullName returns the user's full name
 func (u *User) FullName() string {
 	return u.FirstName + " " + u.LastName
 }
+
+// IsEmailVerified reports whether the user has confirmed their email address
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// HasRole reports whether the user has any of the given roles
+func (u *User) HasRole(roles ...Role) bool {
+	return HasAnyRole(u.Roles, roles...)
+}
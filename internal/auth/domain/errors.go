@@ -4,10 +4,80 @@ import "errors"
 
 // Domain-level errors
 var (
-	ErrUserNotFound        = errors.New("user not found")
-	ErrUsernameExists      = errors.New("username already exists")
-	ErrEmailExists         = errors.New("email already exists")
-	ErrInvalidPassword     = errors.New("invalid password")
-	ErrJWTGeneration       = errors.New("failed to generate JWT token")
-	ErrInternalServerError = errors.New("internal server error")
+	ErrUserNotFound                = errors.New("user not found")
+	ErrUsernameExists              = errors.New("username already exists")
+	ErrEmailExists                 = errors.New("email already exists")
+	ErrInvalidUsername             = errors.New("username must not contain @")
+	ErrInvalidPassword             = errors.New("invalid password")
+	ErrJWTGeneration               = errors.New("failed to generate JWT token")
+	ErrInvalidRefreshToken         = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused          = errors.New("refresh token has already been used")
+	ErrInvalidToken                = errors.New("invalid token")
+	ErrTokenRevoked                = errors.New("token has been revoked")
+	ErrTokenMalformed              = errors.New("token is malformed")
+	ErrTokenExpired                = errors.New("token has expired")
+	ErrTokenNotYetValid            = errors.New("token is not valid yet")
+	ErrTokenSignatureInvalid       = errors.New("token signature is invalid")
+	ErrTokenAlgorithm              = errors.New("token signing algorithm is not allowed")
+	ErrTokenInvalidIssuer          = errors.New("token has an invalid issuer")
+	ErrTokenInvalidAudience        = errors.New("token has an invalid audience")
+	ErrTokenInvalidClaims          = errors.New("token claims are missing or invalid")
+	ErrSessionRevoked              = errors.New("session has been revoked")
+	ErrSessionNotFound             = errors.New("session not found")
+	ErrInvalidScope                = errors.New("scopes must be permissions granted to the account")
+	ErrInvalidExpiry               = errors.New("expiry must be in the future")
+	ErrPersonalAccessTokenNotFound = errors.New("personal access token not found")
+	ErrEmailNotVerified            = errors.New("email address has not been verified")
+	ErrInvalidVerification         = errors.New("invalid or expired verification token")
+	ErrTooManyRequests             = errors.New("too many requests, please try again later")
+	ErrInvalidResetToken           = errors.New("invalid or expired password reset token")
+	ErrInvalidMagicLink            = errors.New("invalid or expired login link, or it was requested from another device")
+	ErrRegistrationClosed          = errors.New("registration is closed")
+	ErrInviteRequired              = errors.New("an invite code is required to register")
+	ErrInvalidInvite               = errors.New("invite code is invalid, expired or used up")
+	ErrInviteNotFound              = errors.New("invite not found")
+	ErrInvalidDateOfBirth          = errors.New("date of birth is invalid")
+	ErrBelowMinimumAge             = errors.New("you are below the minimum age to use Musicfy")
+	ErrAvatarTooLarge              = errors.New("avatar image is too large")
+	ErrUnsupportedImage            = errors.New("avatar must be a JPEG, PNG or GIF image")
+	ErrInvalidImageDimensions      = errors.New("avatar image is too small or too large")
+	ErrAvatarConflict              = errors.New("avatar was changed by another upload, please try again")
+	ErrIncorrectPassword           = errors.New("current password is incorrect")
+	ErrPasswordUnchanged           = errors.New("new password must differ from the current password")
+	ErrAccountLocked               = errors.New("account is temporarily locked due to too many failed login attempts")
+	ErrTooManyLoginAttempts        = errors.New("too many failed login attempts from this address, please try again later")
+	ErrTwoFactorAlreadyEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled         = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTwoFactorCode        = errors.New("invalid two-factor authentication code")
+	ErrInvalidChallenge            = errors.New("invalid or expired two-factor challenge")
+	ErrAccountSuspended            = errors.New("account has been suspended")
+	ErrPasswordResetRequired       = errors.New("a password reset is required; use the link sent by email or request a new one")
+	ErrUserSuspended               = errors.New("user is already suspended")
+	ErrUserNotSuspended            = errors.New("user is not suspended")
+	ErrCannotManageOwnAccount      = errors.New("admins cannot suspend, reset or delete their own account")
+	ErrInternalServerError         = errors.New("internal server error")
 )
+
+// ErrWeakPassword is matched by every PasswordPolicyError
+var ErrWeakPassword = errors.New("password does not meet the password policy")
+
+// PasswordViolation is one rule of the password policy that a password breaks
+type PasswordViolation struct {
+	Code    string
+	Message string
+}
+
+// PasswordPolicyError lists every rule of the password policy that a password breaks
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+// Error implements the error interface
+func (e *PasswordPolicyError) Error() string {
+	return ErrWeakPassword.Error()
+}
+
+// Is makes errors.Is(err, ErrWeakPassword) report true
+func (e *PasswordPolicyError) Is(target error) bool {
+	return target == ErrWeakPassword
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"musicfy/internal/auth/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthEventFilter selects authentication events; zero fields do not filter
+type AuthEventFilter struct {
+	UserID *uuid.UUID
+	Type   entities.AuthEventType
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
+
+// AuthEventRepository defines the interface for authentication audit log data access
+type AuthEventRepository interface {
+	// Create inserts a new event into the database
+	Create(event *entities.AuthEvent) error
+
+	// Find lists the events matching a filter, newest first
+	Find(filter AuthEventFilter) ([]*entities.AuthEvent, error)
+}
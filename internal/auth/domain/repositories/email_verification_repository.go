@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"musicfy/internal/auth/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerificationRepository defines the interface for email verification token data access
+type EmailVerificationRepository interface {
+	// Create inserts a new verification token into the database
+	Create(token *entities.EmailVerificationToken) error
+
+	// FindByHash finds a verification token by the hash of its value
+	FindByHash(tokenHash string) (*entities.EmailVerificationToken, error)
+
+	// MarkUsed marks a token as used. It returns false if the token had already been used.
+	MarkUsed(id uuid.UUID) (bool, error)
+
+	// CountCreatedSince counts the tokens issued to a user since the given time
+	CountCreatedSince(userID uuid.UUID, since time.Time) (int, error)
+}
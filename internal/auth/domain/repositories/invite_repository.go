@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"musicfy/internal/auth/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// InviteRepository defines the interface for invite data access
+type InviteRepository interface {
+	// Create inserts a new invite into the database
+	Create(invite *entities.Invite) error
+
+	// FindByID finds an invite by ID
+	FindByID(id uuid.UUID) (*entities.Invite, error)
+
+	// FindByCodeHash finds an invite by the hash of its code
+	FindByCodeHash(codeHash string) (*entities.Invite, error)
+
+	// List returns a page of invites, newest first
+	List(limit, offset int) ([]*entities.Invite, error)
+
+	// ListRedemptions returns the redemptions of an invite, oldest first
+	ListRedemptions(inviteID uuid.UUID) ([]*entities.InviteRedemption, error)
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"musicfy/internal/auth/domain/entities"
+	"time"
+)
+
+// LoginThrottleRepository defines the interface for failed login attempt tracking
+type LoginThrottleRepository interface {
+	// Find finds the throttle state for a key
+	Find(key string) (*entities.LoginThrottle, error)
+
+	// RecordFailure atomically counts a failed attempt and returns the updated state.
+	// The count restarts if the previous failure and any lock ended before resetBefore.
+	RecordFailure(key string, resetBefore time.Time) (*entities.LoginThrottle, error)
+
+	// Lock blocks login attempts for a key until the given time
+	Lock(key string, until time.Time) error
+
+	// Reset clears failed attempts and any lock for a key
+	Reset(key string) error
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"musicfy/internal/auth/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MagicLinkRepository defines the interface for magic link token data access
+type MagicLinkRepository interface {
+	// Create inserts a new magic link token into the database
+	Create(token *entities.MagicLinkToken) error
+
+	// FindByHash finds a magic link token by the hash of its value
+	FindByHash(tokenHash string) (*entities.MagicLinkToken, error)
+
+	// MarkUsed marks a token as used. It returns false if the token had already been used.
+	MarkUsed(id uuid.UUID) (bool, error)
+
+	// CountCreatedSince counts the tokens issued to a user since the given time
+	CountCreatedSince(userID uuid.UUID, since time.Time) (int, error)
+}
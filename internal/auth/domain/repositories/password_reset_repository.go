@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"musicfy/internal/auth/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordResetRepository defines the interface for password reset token data access
+type PasswordResetRepository interface {
+	// Create inserts a new reset token into the database
+	Create(token *entities.PasswordResetToken) error
+
+	// FindByHash finds a reset token by the hash of its value
+	FindByHash(tokenHash string) (*entities.PasswordResetToken, error)
+
+	// MarkUsed marks a token as used. It returns false if the token had already been used.
+	MarkUsed(id uuid.UUID) (bool, error)
+
+	// MarkAllUsedForUser marks every outstanding token of a user as used
+	MarkAllUsedForUser(userID uuid.UUID) error
+
+	// CountCreatedSince counts the tokens issued to a user since the given time
+	CountCreatedSince(userID uuid.UUID, since time.Time) (int, error)
+}
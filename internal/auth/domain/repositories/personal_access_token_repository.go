@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"musicfy/internal/auth/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersonalAccessTokenRepository defines the interface for personal access token data access
+type PersonalAccessTokenRepository interface {
+	// Create inserts a new personal access token into the database
+	Create(token *entities.PersonalAccessToken) error
+
+	// FindByHash finds a personal access token by the hash of its value
+	FindByHash(tokenHash string) (*entities.PersonalAccessToken, error)
+
+	// ListByUserID lists the tokens of a user that have not been revoked, newest first
+	ListByUserID(userID uuid.UUID) ([]*entities.PersonalAccessToken, error)
+
+	// MarkUsed records when a token was last used
+	MarkUsed(id uuid.UUID, usedAt time.Time) error
+
+	// Revoke revokes a token belonging to the user.
+	// It returns false if there was no such active token.
+	Revoke(id, userID uuid.UUID) (bool, error)
+
+	// RevokeAllForUser revokes every active token belonging to a user
+	RevokeAllForUser(userID uuid.UUID) error
+}
@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"musicfy/internal/auth/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenRepository defines the interface for refresh token data access
+type RefreshTokenRepository interface {
+	// Create inserts a new refresh token into the database
+	Create(token *entities.RefreshToken) error
+
+	// FindByHash finds a refresh token by the hash of its value
+	FindByHash(tokenHash string) (*entities.RefreshToken, error)
+
+	// MarkRotated revokes a token in favour of its successor.
+	// It returns false if the token had already been revoked.
+	MarkRotated(id, replacedBy uuid.UUID) (bool, error)
+
+	// RevokeFamily revokes every active token in a token family
+	RevokeFamily(familyID uuid.UUID) error
+
+	// RevokeAllForUser revokes every active token belonging to a user
+	RevokeAllForUser(userID uuid.UUID) error
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RevokedTokenRepository defines the interface for the access token revocation store
+type RevokedTokenRepository interface {
+	// Revoke records an access token as revoked until it expires
+	Revoke(tokenID, userID uuid.UUID, expiresAt time.Time) error
+
+	// IsRevoked reports whether an access token has been revoked
+	IsRevoked(tokenID uuid.UUID) (bool, error)
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"musicfy/internal/auth/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// SessionRepository defines the interface for session data access
+type SessionRepository interface {
+	// Create inserts a new session into the database
+	Create(session *entities.Session) error
+
+	// FindByID finds a session by ID
+	FindByID(id uuid.UUID) (*entities.Session, error)
+
+	// ListActiveByUserID lists the sessions of a user that are neither revoked nor expired,
+	// most recently seen first
+	ListActiveByUserID(userID uuid.UUID) ([]*entities.Session, error)
+
+	// UpdateActivity stores the last-seen time, client address, user agent and expiry of a session
+	UpdateActivity(session *entities.Session) error
+
+	// Revoke revokes a session
+	Revoke(id uuid.UUID) error
+
+	// RevokeAllForUser revokes every active session belonging to a user
+	RevokeAllForUser(userID uuid.UUID) error
+}
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"musicfy/internal/auth/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorRepository defines the interface for TOTP enrollment and recovery code data access
+type TwoFactorRepository interface {
+	// FindByUserID finds the TOTP enrollment of a user
+	FindByUserID(userID uuid.UUID) (*entities.TwoFactor, error)
+
+	// SavePending stores a new unconfirmed enrollment, replacing any earlier unconfirmed one
+	SavePending(twoFactor *entities.TwoFactor) error
+
+	// Confirm enables a pending enrollment and records the time step of the confirming code
+	Confirm(userID uuid.UUID, step int64) error
+
+	// UseStep records a time step as used. It returns false if that or a later step was already used.
+	UseStep(userID uuid.UUID, step int64) (bool, error)
+
+	// Delete removes the enrollment and all recovery codes of a user
+	Delete(userID uuid.UUID) error
+
+	// ReplaceRecoveryCodes discards the recovery codes of a user and stores new code hashes
+	ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error
+
+	// UseRecoveryCode marks an unused recovery code as used. It returns false if no such code exists.
+	UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error)
+}
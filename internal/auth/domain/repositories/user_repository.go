@@ -2,27 +2,65 @@ package repositories
 
 import (
 	"musicfy/internal/auth/domain/entities"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// UserFilter selects users for listing; zero fields do not filter
+type UserFilter struct {
+	// Search matches part of the username, email or name, ignoring case
+	Search        string
+	Status        entities.UserStatus
+	Role          entities.Role
+	EmailVerified *bool
+	Limit         int
+	Offset        int
+}
+
 // UserRepository defines the interface for user data access
 type UserRepository interface {
-	// Create inserts a new user into the database
+	// Create inserts a new user into the database; it returns ErrUsernameExists or
+	// ErrEmailExists when either is already taken
 	Create(user *entities.User) error
 
-	// FindByUsername finds a user by username
+	// CreateWithInvite inserts a new user and records the redemption of an invite in one
+	// transaction. It returns false, without creating the user, if the invite has no uses
+	// left or has expired.
+	CreateWithInvite(user *entities.User, inviteID uuid.UUID) (bool, error)
+
+	// FindByUsername finds a user by username, ignoring case
 	FindByUsername(username string) (*entities.User, error)
 
-	// FindByEmail finds a user by email
+	// FindByEmail finds a user by email, ignoring case
 	FindByEmail(email string) (*entities.User, error)
 
-	// FindByUsernameOrEmail finds a user by username or email
-	FindByUsernameOrEmail(usernameOrEmail string) (*entities.User, error)
-
 	// FindByID finds a user by ID
 	FindByID(id uuid.UUID) (*entities.User, error)
 
-	// Update updates an existing user in the database
+	// List returns a page of the users matching a filter, newest first, and the number of
+	// matching users
+	List(filter UserFilter) ([]*entities.User, int, error)
+
+	// Update updates an existing user in the database; it returns ErrUsernameExists or
+	// ErrEmailExists when either is already taken by another user
 	Update(user *entities.User) error
+
+	// UpdatePasswordHash replaces the password hash of a user, provided it still equals
+	// oldHash; it reports whether the hash was replaced
+	UpdatePasswordHash(id uuid.UUID, newHash, oldHash string) (bool, error)
+
+	// Unsuspend makes a suspended user active again; it reports whether the user was suspended
+	Unsuspend(id uuid.UUID) (bool, error)
+
+	// SetEmailVerified marks the email address of a user as verified at the given time,
+	// provided it is still email and not verified yet
+	SetEmailVerified(id uuid.UUID, email string, at time.Time) error
+
+	// SetAvatarKey replaces the avatar key of a user, provided it still equals previousKey;
+	// it reports whether the key was replaced
+	SetAvatarKey(id uuid.UUID, key, previousKey string) (bool, error)
+
+	// Delete removes a user together with their tokens, sessions and two-factor enrollment
+	Delete(id uuid.UUID) error
 }
@@ -0,0 +1,242 @@
+package usecases
+
+import (
+	"log"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Page sizes of user listings
+const (
+	defaultUserListLimit = 20
+	maxUserListLimit     = 100
+)
+
+// AdminUseCase handles account administration business logic
+type AdminUseCase struct {
+	userRepository          repositories.UserRepository
+	loginThrottleRepository repositories.LoginThrottleRepository
+	authEventRepository     repositories.AuthEventRepository
+	twoFactorRepository     repositories.TwoFactorRepository
+	sessionRepository       repositories.SessionRepository
+	inviteRepository        repositories.InviteRepository
+	authUseCase             *AuthUseCase
+}
+
+// UserDetails describes an account as seen by an admin
+type UserDetails struct {
+	User             *entities.User
+	TwoFactorEnabled bool
+	ActiveSessions   int
+	LockedUntil      *time.Time
+}
+
+// NewAdminUseCase creates a new admin use case. Sign-outs, password reset emails and audit
+// events go through the auth use case so that they behave the same as for users.
+func NewAdminUseCase(deps AuthDependencies, authUseCase *AuthUseCase) *AdminUseCase {
+	return &AdminUseCase{
+		userRepository:          deps.UserRepository,
+		loginThrottleRepository: deps.LoginThrottleRepository,
+		authEventRepository:     deps.AuthEventRepository,
+		twoFactorRepository:     deps.TwoFactorRepository,
+		sessionRepository:       deps.SessionRepository,
+		inviteRepository:        deps.InviteRepository,
+		authUseCase:             authUseCase,
+	}
+}
+
+// ListUsers returns a page of the users matching a filter and the number of matching users
+func (uc *AdminUseCase) ListUsers(filter repositories.UserFilter) ([]*entities.User, int, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultUserListLimit
+	}
+	if filter.Limit > maxUserListLimit {
+		filter.Limit = maxUserListLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return uc.userRepository.List(filter)
+}
+
+// GetUser returns a user with their two-factor, session and lockout state
+func (uc *AdminUseCase) GetUser(userID uuid.UUID) (*UserDetails, error) {
+	// Find user
+	user, err := uc.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	details := &UserDetails{User: user}
+
+	// Collect account state
+	twoFactor, err := uc.twoFactorRepository.FindByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	details.TwoFactorEnabled = twoFactor != nil && twoFactor.IsEnabled()
+
+	sessions, err := uc.sessionRepository.ListActiveByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	details.ActiveSessions = len(sessions)
+
+	throttle, err := uc.loginThrottleRepository.Find(entities.AccountThrottleKey(user.ID))
+	if err != nil {
+		return nil, err
+	}
+	if throttle != nil && throttle.IsLocked() {
+		details.LockedUntil = throttle.LockedUntil
+	}
+
+	return details, nil
+}
+
+// SuspendUser blocks a user from signing in, signs them out everywhere and revokes their
+// personal access tokens
+func (uc *AdminUseCase) SuspendUser(adminID, userID uuid.UUID, reason string, client ClientInfo) error {
+	// Find user
+	user, err := uc.getManagedUser(adminID, userID)
+	if err != nil {
+		return err
+	}
+	if user.Status == entities.UserStatusSuspended {
+		return domain.ErrUserSuspended
+	}
+
+	// Save the status and invalidate existing sessions
+	user.Status = entities.UserStatusSuspended
+	if err := uc.authUseCase.revokeAllTokens(user); err != nil {
+		return err
+	}
+
+	metadata := map[string]string{"admin_id": adminID.String()}
+	if reason != "" {
+		metadata["reason"] = reason
+	}
+	uc.authUseCase.recordEvent(entities.AuthEventAccountSuspended, user.ID, client, metadata)
+	return nil
+}
+
+// UnsuspendUser lets a suspended user sign in again
+func (uc *AdminUseCase) UnsuspendUser(adminID, userID uuid.UUID, client ClientInfo) error {
+	// Find user
+	user, err := uc.getManagedUser(adminID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Only the status is written, and only if the user is still suspended
+	unsuspended, err := uc.userRepository.Unsuspend(user.ID)
+	if err != nil {
+		return err
+	}
+	if !unsuspended {
+		return domain.ErrUserNotSuspended
+	}
+
+	uc.authUseCase.recordEvent(entities.AuthEventAccountUnsuspended, user.ID, client, map[string]string{"admin_id": adminID.String()})
+	return nil
+}
+
+// ForcePasswordReset signs a user out everywhere, revokes their personal access tokens and
+// emails them a reset link; they cannot sign in until they have chosen a new password
+func (uc *AdminUseCase) ForcePasswordReset(adminID, userID uuid.UUID, client ClientInfo) error {
+	// Find user
+	user, err := uc.getManagedUser(adminID, userID)
+	if err != nil {
+		return err
+	}
+	if user.Status == entities.UserStatusSuspended {
+		return domain.ErrUserSuspended
+	}
+
+	// Save the status and invalidate every credential but the password
+	user.Status = entities.UserStatusPasswordResetRequired
+	if err := uc.authUseCase.revokeAllTokens(user); err != nil {
+		return err
+	}
+
+	// The user can request another link through the forgot password flow if this fails
+	if err := uc.authUseCase.sendPasswordResetEmail(user); err != nil {
+		log.Printf("Failed to send password reset email to user %s: %v", user.ID, err)
+	}
+
+	uc.authUseCase.recordEvent(entities.AuthEventPasswordResetForced, user.ID, client, map[string]string{"admin_id": adminID.String()})
+	return nil
+}
+
+// DeleteUser permanently removes a user and everything that belongs to them except their
+// audit log, which keeps the record of the deletion
+func (uc *AdminUseCase) DeleteUser(adminID, userID uuid.UUID, client ClientInfo) error {
+	// Find user
+	user, err := uc.getManagedUser(adminID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.userRepository.Delete(user.ID); err != nil {
+		return err
+	}
+	if user.AvatarKey != "" {
+		uc.authUseCase.deleteAvatarFiles(user.AvatarKey)
+	}
+
+	uc.authUseCase.recordEvent(entities.AuthEventAccountDeleted, user.ID, client, map[string]string{
+		"admin_id": adminID.String(),
+		"username": user.Username,
+		"email":    user.Email,
+	})
+	return nil
+}
+
+// UnlockUser lifts a lockout caused by failed login attempts
+func (uc *AdminUseCase) UnlockUser(adminID, userID uuid.UUID, client ClientInfo) error {
+	// Find user
+	user, err := uc.getUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.loginThrottleRepository.Reset(entities.AccountThrottleKey(user.ID)); err != nil {
+		return err
+	}
+
+	uc.authUseCase.recordEvent(entities.AuthEventAccountUnlocked, user.ID, client, map[string]string{"admin_id": adminID.String()})
+	return nil
+}
+
+// ListAuthEvents queries the audit log of all users, newest first
+func (uc *AdminUseCase) ListAuthEvents(filter repositories.AuthEventFilter) ([]*entities.AuthEvent, error) {
+	return uc.authEventRepository.Find(normalizeAuthEventFilter(filter))
+}
+
+// AvatarURLs returns the URLs of the avatar renditions of a user, see AuthUseCase.AvatarURLs
+func (uc *AdminUseCase) AvatarURLs(user *entities.User) map[int]string {
+	return uc.authUseCase.AvatarURLs(user)
+}
+
+// getManagedUser retrieves a user an admin is about to act on; admins cannot lock
+// themselves out
+func (uc *AdminUseCase) getManagedUser(adminID, userID uuid.UUID) (*entities.User, error) {
+	if adminID == userID {
+		return nil, domain.ErrCannotManageOwnAccount
+	}
+	return uc.getUser(userID)
+}
+
+// getUser retrieves a user by ID
+func (uc *AdminUseCase) getUser(id uuid.UUID) (*entities.User, error) {
+	user, err := uc.userRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return user, nil
+}
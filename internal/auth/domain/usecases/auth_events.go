@@ -0,0 +1,87 @@
+package usecases
+
+import (
+	"log"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"net/mail"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Page sizes of audit log listings
+const (
+	defaultAuthEventLimit = 50
+	maxAuthEventLimit     = 200
+)
+
+// Reasons recorded with failed login events
+const (
+	loginFailureUnknownUser          = "unknown_user"
+	loginFailureInvalidPassword      = "invalid_password"
+	loginFailureBlocked              = "blocked"
+	loginFailureEmailNotVerified     = "email_not_verified"
+	loginFailureAccountStatus        = "account_status"
+	loginFailureInvalidTwoFactorCode = "invalid_two_factor_code"
+	loginFailureDeviceMismatch       = "magic_link_device_mismatch"
+)
+
+// ListAuthEvents returns a page of the user's own audit log, newest first
+func (uc *AuthUseCase) ListAuthEvents(userID uuid.UUID, filter repositories.AuthEventFilter) ([]*entities.AuthEvent, error) {
+	filter.UserID = &userID
+	return uc.authEventRepository.Find(normalizeAuthEventFilter(filter))
+}
+
+// recordEvent adds an event about a user to the audit log
+func (uc *AuthUseCase) recordEvent(eventType entities.AuthEventType, userID uuid.UUID, client ClientInfo, metadata map[string]string) {
+	uc.saveEvent(entities.NewAuthEvent(&userID, eventType, client.IPAddress, truncate(client.UserAgent, maxUserAgentLength), metadata))
+}
+
+// recordFailedLoginEvent adds a failed login to the audit log; user is nil when the account is unknown
+func (uc *AuthUseCase) recordFailedLoginEvent(user *entities.User, usernameOrEmail, reason string, client ClientInfo) {
+	metadata := map[string]string{"reason": reason}
+	if user == nil {
+		// Keep what was typed so that probing for accounts can be seen, but only when it is an
+		// email address: users sometimes type their password into the identifier field, and
+		// a username cannot be told apart from a password
+		if email := entities.NormalizeEmail(usernameOrEmail); isEmailAddress(email) {
+			metadata["username_or_email"] = truncate(email, maxDeviceNameLength)
+		}
+		uc.saveEvent(entities.NewAuthEvent(nil, entities.AuthEventLoginFailed, client.IPAddress, truncate(client.UserAgent, maxUserAgentLength), metadata))
+		return
+	}
+	uc.recordEvent(entities.AuthEventLoginFailed, user.ID, client, metadata)
+}
+
+// isEmailAddress reports whether s is a bare email address whose domain has a dot
+func isEmailAddress(s string) bool {
+	address, err := mail.ParseAddress(s)
+	if err != nil || address.Name != "" || address.Address != s {
+		return false
+	}
+	_, domain, _ := strings.Cut(address.Address, "@")
+	return strings.Contains(domain, ".") && !strings.HasSuffix(domain, ".")
+}
+
+// saveEvent stores an audit log event. Failures are only logged so that an unavailable
+// audit log does not lock users out.
+func (uc *AuthUseCase) saveEvent(event *entities.AuthEvent) {
+	if err := uc.authEventRepository.Create(event); err != nil {
+		log.Printf("Failed to record %s auth event: %v", event.Type, err)
+	}
+}
+
+// normalizeAuthEventFilter applies the default page size and caps it
+func normalizeAuthEventFilter(filter repositories.AuthEventFilter) repositories.AuthEventFilter {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuthEventLimit
+	}
+	if filter.Limit > maxAuthEventLimit {
+		filter.Limit = maxAuthEventLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return filter
+}
@@ -0,0 +1,38 @@
+package usecases
+
+import "testing"
+
+func TestRecordFailedLoginEventForUnknownUser(t *testing.T) {
+	tests := []struct {
+		name  string
+		typed string
+		want  string // recorded username_or_email; empty when nothing is kept
+	}{
+		{name: "email address", typed: "Someone@Example.com", want: "someone@example.com"},
+		{name: "username", typed: "johndoe", want: ""},
+		{name: "password typed as identifier", typed: "Tr0ub4dor&3", want: ""},
+		{name: "password containing an at sign", typed: "p@ssw0rd", want: ""},
+		{name: "address with a display name", typed: "John <john@example.com>", want: ""},
+		{name: "domain without a dot", typed: "john@localhost", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, _ := newAuthTestUseCase(t)
+
+			tc.recordFailedLoginEvent(nil, tt.typed, loginFailureUnknownUser, ClientInfo{})
+
+			if len(tc.events.events) != 1 {
+				t.Fatalf("recorded %d events, want 1", len(tc.events.events))
+			}
+			event := tc.events.events[0]
+			got, ok := event.Metadata["username_or_email"]
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("username_or_email = %q (present %v), want %q", got, ok, tt.want)
+			}
+			if event.Metadata["reason"] != loginFailureUnknownUser {
+				t.Errorf("reason = %q, want %q", event.Metadata["reason"], loginFailureUnknownUser)
+			}
+		})
+	}
+}
@@ -2,82 +2,442 @@ package usecases
 
 import (
 	"errors"
+	"log"
 	"musicfy/internal/auth/domain"
 	"musicfy/internal/auth/domain/entities"
 	"musicfy/internal/auth/domain/repositories"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // AuthUseCase handles authentication business logic
 type AuthUseCase struct {
-	userRepository repositories.UserRepository
-	jwtService     JWTService
+	userRepository                repositories.UserRepository
+	refreshTokenRepository        repositories.RefreshTokenRepository
+	personalAccessTokenRepository repositories.PersonalAccessTokenRepository
+	sessionRepository             repositories.SessionRepository
+	revokedTokenRepository        repositories.RevokedTokenRepository
+	emailVerificationRepository   repositories.EmailVerificationRepository
+	passwordResetRepository       repositories.PasswordResetRepository
+	loginThrottleRepository       repositories.LoginThrottleRepository
+	twoFactorRepository           repositories.TwoFactorRepository
+	magicLinkRepository           repositories.MagicLinkRepository
+	inviteRepository              repositories.InviteRepository
+	authEventRepository           repositories.AuthEventRepository
+	jwtService                    JWTService
+	passwordHasher                PasswordHasher
+	breachedPasswordChecker       BreachedPasswordChecker
+	totpService                   TOTPService
+	mailer                        Mailer
+	fileStorage                   FileStorage
+	imageProcessor                ImageProcessor
+	settings                      AuthSettings
+}
+
+// AuthDependencies groups the collaborators required by AuthUseCase
+type AuthDependencies struct {
+	UserRepository                repositories.UserRepository
+	RefreshTokenRepository        repositories.RefreshTokenRepository
+	PersonalAccessTokenRepository repositories.PersonalAccessTokenRepository
+	SessionRepository             repositories.SessionRepository
+	RevokedTokenRepository        repositories.RevokedTokenRepository
+	EmailVerificationRepository   repositories.EmailVerificationRepository
+	PasswordResetRepository       repositories.PasswordResetRepository
+	LoginThrottleRepository       repositories.LoginThrottleRepository
+	TwoFactorRepository           repositories.TwoFactorRepository
+	MagicLinkRepository           repositories.MagicLinkRepository
+	InviteRepository              repositories.InviteRepository
+	AuthEventRepository           repositories.AuthEventRepository
+	JWTService                    JWTService
+	PasswordHasher                PasswordHasher
+	BreachedPasswordChecker       BreachedPasswordChecker
+	TOTPService                   TOTPService
+	Mailer                        Mailer
+	FileStorage                   FileStorage
+	ImageProcessor                ImageProcessor
+}
+
+// AuthSettings holds the configurable behaviour of AuthUseCase
+type AuthSettings struct {
+	// PublicURL is the base URL used to build links sent by email
+	PublicURL string
+
+	// RegistrationMode decides who may register
+	RegistrationMode RegistrationMode
+
+	// MinimumAge is the age a user must have reached to register
+	MinimumAge int
+
+	// RequireEmailVerification makes LoginUser refuse unverified accounts
+	RequireEmailVerification bool
+
+	// EmailVerificationExpiry is the lifetime of email verification tokens
+	EmailVerificationExpiry time.Duration
+
+	// VerificationResendInterval is the minimum time between two verification emails
+	VerificationResendInterval time.Duration
+
+	// VerificationMaxPerHour caps the number of verification emails per user and hour
+	VerificationMaxPerHour int
+
+	// PasswordResetURL is the page that password reset links point to
+	PasswordResetURL string
+
+	// PasswordResetExpiry is the lifetime of password reset tokens
+	PasswordResetExpiry time.Duration
+
+	// MagicLinkURL is the page that magic login links point to
+	MagicLinkURL string
+
+	// MagicLinkExpiry is the lifetime of magic login links
+	MagicLinkExpiry time.Duration
+
+	// MagicLinkMaxPerHour caps the number of magic login emails per user and hour
+	MagicLinkMaxPerHour int
+
+	// MagicLinkIPMaxPerHour caps the number of magic links requested per client IP and hour
+	MagicLinkIPMaxPerHour int
+
+	// AvatarMaxBytes is the largest accepted avatar upload
+	AvatarMaxBytes int
+
+	// AvatarMaxDimension is the largest accepted width and height of an avatar upload
+	AvatarMaxDimension int
+
+	// AvatarMaxPixels is the largest accepted area, width times height, of an avatar upload
+	AvatarMaxPixels int
+
+	// Lockout configures brute-force protection for LoginUser
+	Lockout LockoutPolicy
+
+	// PasswordPolicy decides which new passwords are accepted
+	PasswordPolicy PasswordPolicy
+}
+
+// AuthTokens holds the credentials issued to a client after authentication
+type AuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    time.Duration
 }
 
 // NewAuthUseCase creates a new auth use case
-func NewAuthUseCase(userRepo repositories.UserRepository, jwtService JWTService) *AuthUseCase {
+func NewAuthUseCase(deps AuthDependencies, settings AuthSettings) *AuthUseCase {
 	return &AuthUseCase{
-		userRepository: userRepo,
-		jwtService:     jwtService,
+		userRepository:                deps.UserRepository,
+		refreshTokenRepository:        deps.RefreshTokenRepository,
+		personalAccessTokenRepository: deps.PersonalAccessTokenRepository,
+		sessionRepository:             deps.SessionRepository,
+		revokedTokenRepository:        deps.RevokedTokenRepository,
+		emailVerificationRepository:   deps.EmailVerificationRepository,
+		passwordResetRepository:       deps.PasswordResetRepository,
+		loginThrottleRepository:       deps.LoginThrottleRepository,
+		twoFactorRepository:           deps.TwoFactorRepository,
+		magicLinkRepository:           deps.MagicLinkRepository,
+		inviteRepository:              deps.InviteRepository,
+		authEventRepository:           deps.AuthEventRepository,
+		jwtService:                    deps.JWTService,
+		passwordHasher:                deps.PasswordHasher,
+		breachedPasswordChecker:       deps.BreachedPasswordChecker,
+		totpService:                   deps.TOTPService,
+		mailer:                        deps.Mailer,
+		fileStorage:                   deps.FileStorage,
+		imageProcessor:                deps.ImageProcessor,
+		settings:                      settings,
 	}
 }
 
 // RegisterUser handles user registration
-func (uc *AuthUseCase) RegisterUser(firstName, lastName, username, email, password string, age int) error {
-	// Check for duplicate username
-	existingByUsername, err := uc.userRepository.FindByUsername(username)
+func (uc *AuthUseCase) RegisterUser(firstName, lastName, username, email, password string, dateOfBirth time.Time, inviteCode string, client ClientInfo) error {
+	username = entities.NormalizeUsername(username)
+	email = entities.NormalizeEmail(email)
+	if !entities.IsValidUsername(username) {
+		return domain.ErrInvalidUsername
+	}
+
+	// Check the registration mode and the invite code
+	invite, err := uc.checkRegistrationAllowed(inviteCode)
 	if err != nil {
 		return err
 	}
-	if existingByUsername != nil {
-		return domain.ErrUsernameExists
-	}
 
-	// Check for duplicate email
-	existingByEmail, err := uc.userRepository.FindByEmail(email)
-	if err != nil {
+	// Check the date of birth against the minimum age
+	if err := uc.checkDateOfBirth(dateOfBirth); err != nil {
 		return err
 	}
-	if existingByEmail != nil {
-		return domain.ErrEmailExists
+
+	// Check password against the policy
+	if err := uc.checkPassword(password, username, email); err != nil {
+		return err
 	}
 
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := uc.passwordHasher.Hash(password)
 	if err != nil {
 		return errors.New("failed to hash password")
 	}
 
-	// Create user
-	newUser := entities.NewUser(firstName, lastName, username, email, age, string(hashedPassword))
-	return uc.userRepository.Create(newUser)
+	// Create user, using up the invite in the same transaction in case it ran out in the
+	// meantime; the repository reports a taken username or email
+	newUser := entities.NewUser(firstName, lastName, username, email, dateOfBirth, hashedPassword)
+	var metadata map[string]string
+	if invite != nil {
+		redeemed, err := uc.userRepository.CreateWithInvite(newUser, invite.ID)
+		if err != nil {
+			return err
+		}
+		if !redeemed {
+			return domain.ErrInvalidInvite
+		}
+		metadata = map[string]string{"invite_id": invite.ID.String()}
+	} else if err := uc.userRepository.Create(newUser); err != nil {
+		return err
+	}
+	uc.recordEvent(entities.AuthEventRegistered, newUser.ID, client, metadata)
+
+	// Send verification email; the user can request another one if this fails
+	if err := uc.sendVerificationEmail(newUser); err != nil {
+		log.Printf("Failed to send verification email to user %s: %v", newUser.ID, err)
+	}
+
+	return nil
+}
+
+// maxAge bounds plausible ages; older dates of birth are typos
+const maxAge = 130
+
+// checkDateOfBirth rejects dates of birth in the future or implausibly far back, and users
+// younger than the minimum age
+func (uc *AuthUseCase) checkDateOfBirth(dateOfBirth time.Time) error {
+	today := time.Now().UTC()
+	if dateOfBirth.After(today) || entities.AgeOn(dateOfBirth, today) > maxAge {
+		return domain.ErrInvalidDateOfBirth
+	}
+	if entities.AgeOn(dateOfBirth, today) < uc.settings.MinimumAge {
+		return domain.ErrBelowMinimumAge
+	}
+	return nil
 }
 
-// LoginUser handles user login
-func (uc *AuthUseCase) LoginUser(usernameOrEmail, password string) (string, error) {
-	// Find user
-	user, err := uc.userRepository.FindByUsernameOrEmail(usernameOrEmail)
+// LoginUser handles user login. Accounts with two-factor authentication enabled get a
+// challenge token instead of tokens, to be completed with CompleteTwoFactorLogin.
+func (uc *AuthUseCase) LoginUser(usernameOrEmail, password string, client ClientInfo) (*LoginResult, error) {
+	// Find user; both are matched case-insensitively. Usernames cannot contain an @, so
+	// input with one is an email address and never matches another account's username.
+	var user *entities.User
+	var err error
+	if strings.Contains(entities.NormalizeUsername(usernameOrEmail), "@") {
+		usernameOrEmail = entities.NormalizeEmail(usernameOrEmail)
+		user, err = uc.userRepository.FindByEmail(usernameOrEmail)
+	} else {
+		usernameOrEmail = entities.NormalizeUsername(usernameOrEmail)
+		user, err = uc.userRepository.FindByUsername(usernameOrEmail)
+	}
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	// Refuse attempts from blocked clients and against locked accounts
+	if err := uc.checkLoginAllowed(user, client); err != nil {
+		if isLockoutError(err) {
+			uc.recordFailedLoginEvent(user, usernameOrEmail, loginFailureBlocked, client)
+		}
+		return nil, err
 	}
+
 	if user == nil {
-		return "", domain.ErrUserNotFound
+		uc.recordFailedLoginEvent(nil, usernameOrEmail, loginFailureUnknownUser, client)
+		if err := uc.recordFailedLogin(nil, client); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrUserNotFound
 	}
 
 	// Verify password
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return "", domain.ErrInvalidPassword
+	match, err := uc.passwordHasher.Verify(user.PasswordHash, password)
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		uc.recordFailedLoginEvent(user, usernameOrEmail, loginFailureInvalidPassword, client)
+		if err := uc.recordFailedLogin(user, client); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidPassword
+	}
+
+	// Refuse suspended accounts and accounts that have to reset their password; this is
+	// only revealed to someone who knows the password
+	if err := checkAccountStatus(user); err != nil {
+		uc.recordFailedLoginEvent(user, usernameOrEmail, loginFailureAccountStatus, client)
+		return nil, err
+	}
+
+	// Upgrade hashes made with an outdated algorithm or parameters while the password is known
+	uc.rehashPassword(user, password)
+
+	// Refuse unverified accounts when required
+	if uc.settings.RequireEmailVerification && !user.IsEmailVerified() {
+		uc.recordFailedLoginEvent(user, usernameOrEmail, loginFailureEmailNotVerified, client)
+		return nil, domain.ErrEmailNotVerified
+	}
+
+	return uc.completeFirstFactor(user, client)
+}
+
+// RefreshTokens exchanges a refresh token for a new access and refresh token pair.
+// Every refresh token can be used once; presenting a rotated token again revokes
+// the whole token family, since it means the token has leaked.
+// The session the token belongs to records the refresh as activity from the client.
+func (uc *AuthUseCase) RefreshTokens(refreshToken string, client ClientInfo) (*AuthTokens, error) {
+	// Find stored token
+	stored, err := uc.refreshTokenRepository.FindByHash(uc.jwtService.HashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	// Detect reuse of an already rotated token; tokens revoked by a logout or a password
+	// change are merely invalid
+	if stored.IsRotated() {
+		return nil, uc.revokeReusedFamily(stored, client)
+	}
+	if stored.IsRevoked() || stored.IsExpired() {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	// Find the session the token family belongs to
+	session, err := uc.sessionRepository.FindByID(stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.RevokedAt != nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	// Find token owner
+	user, err := uc.userRepository.FindByID(stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+	if err := checkAccountStatus(user); err != nil {
+		return nil, err
 	}
 
-	// Generate token
-	token, err := uc.jwtService.GenerateToken(user.ID, user.Username)
+	// Issue the successor within the same family
+	tokens, successorID, err := uc.issueTokens(user, stored.FamilyID)
 	if err != nil {
-		return "", domain.ErrJWTGeneration
+		return nil, err
+	}
+
+	// Retire the presented token; losing this race means it was used concurrently
+	rotated, err := uc.refreshTokenRepository.MarkRotated(stored.ID, successorID)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, uc.revokeReusedFamily(stored, client)
 	}
 
-	return token, nil
+	// The session now lasts as long as its newest refresh token
+	if err := uc.touchSession(session, client, time.Now().Add(uc.jwtService.RefreshTokenExpiry())); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// AuthenticateToken validates an access token and checks that neither the token nor its
+// session has been revoked. The request is recorded as activity on the session.
+// Personal access tokens are accepted as well.
+func (uc *AuthUseCase) AuthenticateToken(tokenString string, client ClientInfo) (*JWTClaims, error) {
+	if strings.HasPrefix(tokenString, entities.PersonalAccessTokenPrefix) {
+		return uc.authenticatePersonalAccessToken(tokenString)
+	}
+
+	// Validate signature and claims; the error tells why a token was rejected
+	claims, err := uc.jwtService.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the revocation store
+	revoked, err := uc.revokedTokenRepository.IsRevoked(claims.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, domain.ErrTokenRevoked
+	}
+
+	// Find token owner
+	user, err := uc.userRepository.FindByID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrInvalidToken
+	}
+	if err := checkAccountStatus(user); err != nil {
+		return nil, err
+	}
+
+	// Check the session, which is revoked when the user signs the device out
+	session, err := uc.findActiveSession(claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.UserID != claims.UserID {
+		return nil, domain.ErrSessionRevoked
+	}
+
+	// Check tokens invalidated by a logout from all devices
+	if issuedBeforeSignOut(user, claims, session) {
+		return nil, domain.ErrTokenRevoked
+	}
+	if err := uc.touchSession(session, client, session.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// Logout revokes the presented access token and the session it belongs to, which also
+// revokes the refresh tokens issued for the session
+func (uc *AuthUseCase) Logout(claims *JWTClaims, client ClientInfo) error {
+	// Revoke access token
+	if err := uc.revokedTokenRepository.Revoke(claims.TokenID, claims.UserID, claims.ExpiresAt); err != nil {
+		return err
+	}
+
+	if err := uc.revokeSession(claims.SessionID); err != nil {
+		return err
+	}
+
+	uc.recordEvent(entities.AuthEventLogout, claims.UserID, client, map[string]string{"session_id": claims.SessionID.String()})
+	return nil
+}
+
+// LogoutAll revokes every access, refresh and personal access token issued to a user so far
+func (uc *AuthUseCase) LogoutAll(userID uuid.UUID, client ClientInfo) error {
+	user, err := uc.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.revokeAllTokens(user); err != nil {
+		return err
+	}
+
+	uc.recordEvent(entities.AuthEventLogoutAll, user.ID, client, nil)
+	return nil
 }
 
 // GetUserByID retrieves a user by ID
@@ -91,3 +451,144 @@ func (uc *AuthUseCase) GetUserByID(id uuid.UUID) (*entities.User, error) {
 	}
 	return user, nil
 }
+
+// PublicKeys returns the keys other services can use to verify access tokens
+func (uc *AuthUseCase) PublicKeys() []PublicKey {
+	return uc.jwtService.PublicKeys()
+}
+
+// issuedBeforeSignOut reports whether an access token was invalidated by a logout from all
+// devices. Token issue times have second precision, so a token issued during the second of
+// the logout counts as issued before it, unless its session was started afterwards.
+func issuedBeforeSignOut(user *entities.User, claims *JWTClaims, session *entities.Session) bool {
+	if user.TokensValidAfter == nil || session.CreatedAt.After(*user.TokensValidAfter) {
+		return false
+	}
+	return !claims.IssuedAt.After(user.TokensValidAfter.Truncate(time.Second))
+}
+
+// checkAccountStatus returns the error explaining why a user may not sign in, if any
+func checkAccountStatus(user *entities.User) error {
+	switch user.Status {
+	case entities.UserStatusSuspended:
+		return domain.ErrAccountSuspended
+	case entities.UserStatusPasswordResetRequired:
+		return domain.ErrPasswordResetRequired
+	default:
+		return nil
+	}
+}
+
+// rehashPassword replaces the user's password hash if it is outdated. Failures are only
+// logged, since the old hash keeps working. Only the hash is written, and only if it has not
+// changed since the user was loaded, so that concurrent changes to the account are kept.
+func (uc *AuthUseCase) rehashPassword(user *entities.User, password string) {
+	if !uc.passwordHasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	hashedPassword, err := uc.passwordHasher.Hash(password)
+	if err != nil {
+		log.Printf("Failed to rehash password of user %s: %v", user.ID, err)
+		return
+	}
+	if _, err := uc.userRepository.UpdatePasswordHash(user.ID, hashedPassword, user.PasswordHash); err != nil {
+		log.Printf("Failed to store rehashed password of user %s: %v", user.ID, err)
+	}
+}
+
+// completeFirstFactor either signs in a user who passed the first login step or, when
+// two-factor authentication is enabled, issues a challenge for the second step
+func (uc *AuthUseCase) completeFirstFactor(user *entities.User, client ClientInfo) (*LoginResult, error) {
+	// Require a second factor when enabled
+	twoFactor, err := uc.twoFactorRepository.FindByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor != nil && twoFactor.IsEnabled() {
+		challengeToken, err := uc.jwtService.GenerateChallengeToken(user.ID)
+		if err != nil {
+			return nil, domain.ErrJWTGeneration
+		}
+		return &LoginResult{
+			ChallengeToken:     challengeToken,
+			ChallengeExpiresIn: uc.jwtService.ChallengeTokenExpiry(),
+		}, nil
+	}
+
+	tokens, err := uc.completeLogin(user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{Tokens: tokens}, nil
+}
+
+// completeLogin clears failed attempts against the account and starts a new session
+func (uc *AuthUseCase) completeLogin(user *entities.User, client ClientInfo) (*AuthTokens, error) {
+	if err := uc.loginThrottleRepository.Reset(entities.AccountThrottleKey(user.ID)); err != nil {
+		return nil, err
+	}
+
+	tokens, err := uc.startSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.recordEvent(entities.AuthEventLoginSucceeded, user.ID, client, nil)
+	return tokens, nil
+}
+
+// revokeReusedFamily revokes the session of a refresh token that was presented again after
+// rotation and returns ErrRefreshTokenReused
+func (uc *AuthUseCase) revokeReusedFamily(stored *entities.RefreshToken, client ClientInfo) error {
+	if err := uc.revokeSession(stored.FamilyID); err != nil {
+		return err
+	}
+
+	uc.recordEvent(entities.AuthEventRefreshTokenReused, stored.UserID, client, map[string]string{"session_id": stored.FamilyID.String()})
+	return domain.ErrRefreshTokenReused
+}
+
+// issueTokens generates an access token and a refresh token for a session; the session ID
+// doubles as the refresh token family. It also returns the ID of the stored refresh token.
+func (uc *AuthUseCase) issueTokens(user *entities.User, sessionID uuid.UUID) (*AuthTokens, uuid.UUID, error) {
+	// Generate access token
+	accessToken, err := uc.jwtService.GenerateToken(user, sessionID)
+	if err != nil {
+		return nil, uuid.Nil, domain.ErrJWTGeneration
+	}
+
+	// Generate and store refresh token
+	refreshToken, err := uc.jwtService.GenerateRefreshToken()
+	if err != nil {
+		return nil, uuid.Nil, domain.ErrJWTGeneration
+	}
+	stored := entities.NewRefreshToken(user.ID, sessionID, uc.jwtService.HashRefreshToken(refreshToken), uc.jwtService.RefreshTokenExpiry())
+	if err := uc.refreshTokenRepository.Create(stored); err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	return &AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    uc.jwtService.AccessTokenExpiry(),
+	}, stored.ID, nil
+}
+
+// revokeAllTokens invalidates every token issued to the user before now, including personal
+// access tokens, which may have leaked together with whatever prompted the sign-out
+func (uc *AuthUseCase) revokeAllTokens(user *entities.User) error {
+	validAfter := time.Now()
+	user.TokensValidAfter = &validAfter
+	if err := uc.userRepository.Update(user); err != nil {
+		return err
+	}
+
+	if err := uc.sessionRepository.RevokeAllForUser(user.ID); err != nil {
+		return err
+	}
+	if err := uc.refreshTokenRepository.RevokeAllForUser(user.ID); err != nil {
+		return err
+	}
+	return uc.personalAccessTokenRepository.RevokeAllForUser(user.ID)
+}
@@ -0,0 +1,324 @@
+package usecases
+
+import (
+	"errors"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The fakes below embed the interface they implement, so that calling a method a test
+// does not expect panics instead of needing a stub.
+
+// memoryUserRepository keeps users in memory and hands out copies, like a database would
+type memoryUserRepository struct {
+	repositories.UserRepository
+	users map[uuid.UUID]entities.User
+}
+
+func (r *memoryUserRepository) FindByID(id uuid.UUID) (*entities.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+func (r *memoryUserRepository) Update(user *entities.User) error {
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *memoryUserRepository) UpdatePasswordHash(id uuid.UUID, newHash, oldHash string) (bool, error) {
+	user, ok := r.users[id]
+	if !ok || user.PasswordHash != oldHash {
+		return false, nil
+	}
+	user.PasswordHash = newHash
+	r.users[id] = user
+	return true, nil
+}
+
+// memorySessionRepository records created sessions and users signed out everywhere
+type memorySessionRepository struct {
+	repositories.SessionRepository
+	created    []*entities.Session
+	revokedFor []uuid.UUID
+}
+
+func (r *memorySessionRepository) Create(session *entities.Session) error {
+	r.created = append(r.created, session)
+	return nil
+}
+
+func (r *memorySessionRepository) RevokeAllForUser(userID uuid.UUID) error {
+	r.revokedFor = append(r.revokedFor, userID)
+	return nil
+}
+
+// memoryRefreshTokenRepository records created tokens and users signed out everywhere
+type memoryRefreshTokenRepository struct {
+	repositories.RefreshTokenRepository
+	created    []*entities.RefreshToken
+	revokedFor []uuid.UUID
+}
+
+func (r *memoryRefreshTokenRepository) Create(token *entities.RefreshToken) error {
+	r.created = append(r.created, token)
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) RevokeAllForUser(userID uuid.UUID) error {
+	r.revokedFor = append(r.revokedFor, userID)
+	return nil
+}
+
+// memoryPersonalAccessTokenRepository keeps personal access tokens in memory by hash
+type memoryPersonalAccessTokenRepository struct {
+	repositories.PersonalAccessTokenRepository
+	tokens map[string]*entities.PersonalAccessToken
+}
+
+func (r *memoryPersonalAccessTokenRepository) FindByHash(tokenHash string) (*entities.PersonalAccessToken, error) {
+	return r.tokens[tokenHash], nil
+}
+
+func (r *memoryPersonalAccessTokenRepository) MarkUsed(id uuid.UUID, usedAt time.Time) error {
+	return nil
+}
+
+func (r *memoryPersonalAccessTokenRepository) RevokeAllForUser(userID uuid.UUID) error {
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// memoryAuthEventRepository records audit log events
+type memoryAuthEventRepository struct {
+	repositories.AuthEventRepository
+	events []*entities.AuthEvent
+}
+
+func (r *memoryAuthEventRepository) Create(event *entities.AuthEvent) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+// memoryEmailVerificationRepository records issued verification tokens
+type memoryEmailVerificationRepository struct {
+	repositories.EmailVerificationRepository
+	created []*entities.EmailVerificationToken
+}
+
+func (r *memoryEmailVerificationRepository) Create(token *entities.EmailVerificationToken) error {
+	r.created = append(r.created, token)
+	return nil
+}
+
+// recordingMailer keeps the messages it was asked to send
+type recordingMailer struct {
+	sent []EmailMessage
+}
+
+func (m *recordingMailer) Send(message EmailMessage) error {
+	m.sent = append(m.sent, message)
+	return nil
+}
+
+// plainPasswordHasher "hashes" by prefixing, which keeps tests fast and readable
+type plainPasswordHasher struct{}
+
+func (plainPasswordHasher) Hash(password string) (string, error) { return "hashed:" + password, nil }
+
+func (plainPasswordHasher) Verify(hash, password string) (bool, error) {
+	return hash == "hashed:"+password, nil
+}
+
+func (plainPasswordHasher) NeedsRehash(hash string) bool { return false }
+
+// outdatedPasswordHasher asks for every hash to be rehashed
+type outdatedPasswordHasher struct {
+	plainPasswordHasher
+}
+
+func (outdatedPasswordHasher) Hash(password string) (string, error) {
+	return "rehashed:" + password, nil
+}
+
+func (outdatedPasswordHasher) NeedsRehash(hash string) bool { return true }
+
+// stubJWTService issues fixed tokens
+type stubJWTService struct {
+	JWTService
+}
+
+func (stubJWTService) GenerateToken(user *entities.User, sessionID uuid.UUID) (string, error) {
+	return "access-token", nil
+}
+
+func (stubJWTService) GenerateRefreshToken() (string, error) { return "refresh-token", nil }
+
+func (stubJWTService) HashRefreshToken(token string) string { return "hash:" + token }
+
+func (stubJWTService) AccessTokenExpiry() time.Duration { return 15 * time.Minute }
+
+func (stubJWTService) RefreshTokenExpiry() time.Duration { return 24 * time.Hour }
+
+// authTestUseCase bundles an AuthUseCase with the fakes behind it
+type authTestUseCase struct {
+	*AuthUseCase
+	users                *memoryUserRepository
+	sessions             *memorySessionRepository
+	refreshTokens        *memoryRefreshTokenRepository
+	personalAccessTokens *memoryPersonalAccessTokenRepository
+	events               *memoryAuthEventRepository
+	emailVerifications   *memoryEmailVerificationRepository
+	mailer               *recordingMailer
+}
+
+// newAuthTestUseCase returns a use case backed by in-memory fakes and one active user whose
+// password is "Old-password-1"
+func newAuthTestUseCase(t *testing.T) (*authTestUseCase, *entities.User) {
+	t.Helper()
+
+	user := entities.NewUser("Ada", "Lovelace", "ada", "ada@example.com", time.Date(1990, 12, 10, 0, 0, 0, 0, time.UTC), "hashed:Old-password-1")
+	tc := &authTestUseCase{
+		users:                &memoryUserRepository{users: map[uuid.UUID]entities.User{user.ID: *user}},
+		sessions:             &memorySessionRepository{},
+		refreshTokens:        &memoryRefreshTokenRepository{},
+		personalAccessTokens: &memoryPersonalAccessTokenRepository{tokens: map[string]*entities.PersonalAccessToken{}},
+		events:               &memoryAuthEventRepository{},
+		emailVerifications:   &memoryEmailVerificationRepository{},
+		mailer:               &recordingMailer{},
+	}
+	tc.AuthUseCase = &AuthUseCase{
+		userRepository:                tc.users,
+		sessionRepository:             tc.sessions,
+		refreshTokenRepository:        tc.refreshTokens,
+		personalAccessTokenRepository: tc.personalAccessTokens,
+		authEventRepository:           tc.events,
+		emailVerificationRepository:   tc.emailVerifications,
+		mailer:                        tc.mailer,
+		passwordHasher:                plainPasswordHasher{},
+		breachedPasswordChecker:       listedPasswords{},
+		jwtService:                    stubJWTService{},
+	}
+	return tc, user
+}
+
+// addPersonalAccessToken stores a personal access token for the user and returns its value
+func (tc *authTestUseCase) addPersonalAccessToken(userID uuid.UUID) string {
+	value := entities.PersonalAccessTokenPrefix + uuid.NewString()
+	token := entities.NewPersonalAccessToken(userID, "ci", value[:12], hashOpaqueToken(value), []entities.Permission{}, nil)
+	tc.personalAccessTokens.tokens[token.TokenHash] = token
+	return value
+}
+
+func TestLogoutAllRevokesPersonalAccessTokens(t *testing.T) {
+	tc, user := newAuthTestUseCase(t)
+	value := tc.addPersonalAccessToken(user.ID)
+
+	if _, err := tc.authenticatePersonalAccessToken(value); err != nil {
+		t.Fatalf("token rejected before logout: %v", err)
+	}
+	if err := tc.LogoutAll(user.ID, ClientInfo{}); err != nil {
+		t.Fatalf("LogoutAll: %v", err)
+	}
+
+	if _, err := tc.authenticatePersonalAccessToken(value); !errors.Is(err, domain.ErrTokenRevoked) {
+		t.Errorf("token after logout: got %v, want %v", err, domain.ErrTokenRevoked)
+	}
+	if len(tc.sessions.revokedFor) != 1 || len(tc.refreshTokens.revokedFor) != 1 {
+		t.Errorf("sessions and refresh tokens not revoked: %v, %v", tc.sessions.revokedFor, tc.refreshTokens.revokedFor)
+	}
+}
+
+func TestLogoutAllKeepsOtherUsersTokens(t *testing.T) {
+	tc, user := newAuthTestUseCase(t)
+	other := tc.addPersonalAccessToken(uuid.New())
+
+	if err := tc.LogoutAll(user.ID, ClientInfo{}); err != nil {
+		t.Fatalf("LogoutAll: %v", err)
+	}
+
+	token := tc.personalAccessTokens.tokens[hashOpaqueToken(other)]
+	if token.IsRevoked() {
+		t.Error("token of another user was revoked")
+	}
+}
+
+func TestRehashPasswordKeepsConcurrentChanges(t *testing.T) {
+	tc, user := newAuthTestUseCase(t)
+	tc.passwordHasher = outdatedPasswordHasher{}
+
+	// An admin suspends the user while the login that loaded user is still running
+	suspended := *user
+	suspended.Status = entities.UserStatusSuspended
+	tc.users.users[user.ID] = suspended
+
+	tc.rehashPassword(user, "Old-password-1")
+
+	stored, _ := tc.users.FindByID(user.ID)
+	if stored.Status != entities.UserStatusSuspended {
+		t.Errorf("status = %q, want the concurrent suspension kept", stored.Status)
+	}
+	if stored.PasswordHash != "rehashed:Old-password-1" {
+		t.Errorf("password hash = %q, want the rehashed password", stored.PasswordHash)
+	}
+}
+
+func TestRehashPasswordSkipsChangedPassword(t *testing.T) {
+	tc, user := newAuthTestUseCase(t)
+	tc.passwordHasher = outdatedPasswordHasher{}
+
+	// The password is changed while the login that loaded user is still running
+	changed := *user
+	changed.PasswordHash = "hashed:New-password-2"
+	tc.users.users[user.ID] = changed
+
+	tc.rehashPassword(user, "Old-password-1")
+
+	stored, _ := tc.users.FindByID(user.ID)
+	if stored.PasswordHash != "hashed:New-password-2" {
+		t.Errorf("password hash = %q, want the new password kept", stored.PasswordHash)
+	}
+}
+
+func TestIssuedBeforeSignOut(t *testing.T) {
+	signOut := time.Date(2024, 6, 10, 12, 0, 0, 400_000_000, time.UTC)
+	sameSecond := signOut.Truncate(time.Second)
+
+	tests := []struct {
+		name             string
+		validAfter       *time.Time
+		issuedAt         time.Time
+		sessionCreatedAt time.Time
+		want             bool
+	}{
+		{name: "never signed out", validAfter: nil, issuedAt: sameSecond, sessionCreatedAt: sameSecond, want: false},
+		{name: "issued a second earlier", validAfter: &signOut, issuedAt: sameSecond.Add(-time.Second), sessionCreatedAt: sameSecond.Add(-time.Hour), want: true},
+		{name: "issued in the same second", validAfter: &signOut, issuedAt: sameSecond, sessionCreatedAt: sameSecond.Add(-time.Hour), want: true},
+		{name: "issued the next second", validAfter: &signOut, issuedAt: sameSecond.Add(time.Second), sessionCreatedAt: sameSecond.Add(-time.Hour), want: false},
+		{name: "same second on a session started afterwards", validAfter: &signOut, issuedAt: sameSecond, sessionCreatedAt: signOut.Add(time.Millisecond), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &entities.User{TokensValidAfter: tt.validAfter}
+			claims := &JWTClaims{IssuedAt: tt.issuedAt}
+			session := &entities.Session{CreatedAt: tt.sessionCreatedAt}
+
+			if got := issuedBeforeSignOut(user, claims, session); got != tt.want {
+				t.Errorf("issuedBeforeSignOut() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
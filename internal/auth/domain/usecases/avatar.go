@@ -0,0 +1,110 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// avatarSizes are the edge lengths, in pixels, of the square avatar renditions
+var avatarSizes = []int{64, 128, 256}
+
+// avatarMinDimension is the smallest accepted width and height of an uploaded avatar
+const avatarMinDimension = 64
+
+// avatarContentTypes are the accepted avatar formats, as sniffed from the content
+var avatarContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// UpdateAvatar validates an uploaded image, stores it resized to every avatar size and makes
+// it the avatar of the user. The previous avatar is deleted.
+func (uc *AuthUseCase) UpdateAvatar(userID uuid.UUID, content []byte) (*entities.User, error) {
+	// Find user
+	user, err := uc.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check size, format and dimensions before decoding the whole image
+	if len(content) > uc.settings.AvatarMaxBytes {
+		return nil, domain.ErrAvatarTooLarge
+	}
+	info, err := uc.imageProcessor.Inspect(content)
+	if err != nil {
+		return nil, err
+	}
+	if !avatarContentTypes[info.ContentType] {
+		return nil, domain.ErrUnsupportedImage
+	}
+	if info.Width < avatarMinDimension || info.Height < avatarMinDimension ||
+		info.Width > uc.settings.AvatarMaxDimension || info.Height > uc.settings.AvatarMaxDimension ||
+		info.Width*info.Height > uc.settings.AvatarMaxPixels {
+		return nil, domain.ErrInvalidImageDimensions
+	}
+
+	// Store renditions under a new key, so that cached copies of the old avatar are not served
+	renditions, err := uc.imageProcessor.SquareThumbnails(content, avatarSizes)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("avatars/%s/%s", user.ID, uuid.New())
+	for _, size := range avatarSizes {
+		if err := uc.fileStorage.Put(avatarFileKey(key, size), renditions[size], "image/jpeg"); err != nil {
+			uc.deleteAvatarFiles(key)
+			return nil, err
+		}
+	}
+
+	// Save the new key only if no other upload replaced the avatar in the meantime
+	previousKey := user.AvatarKey
+	replaced, err := uc.userRepository.SetAvatarKey(user.ID, key, previousKey)
+	if err != nil {
+		uc.deleteAvatarFiles(key)
+		return nil, err
+	}
+	if !replaced {
+		uc.deleteAvatarFiles(key)
+		return nil, domain.ErrAvatarConflict
+	}
+	if previousKey != "" {
+		uc.deleteAvatarFiles(previousKey)
+	}
+
+	return uc.GetUserByID(user.ID)
+}
+
+// AvatarURLs returns the URLs of the avatar renditions of a user by edge length, or nil
+// when the user has no avatar
+func (uc *AuthUseCase) AvatarURLs(user *entities.User) map[int]string {
+	if user.AvatarKey == "" {
+		return nil
+	}
+
+	urls := make(map[int]string, len(avatarSizes))
+	for _, size := range avatarSizes {
+		urls[size] = uc.fileStorage.URL(avatarFileKey(user.AvatarKey, size))
+	}
+	return urls
+}
+
+// deleteAvatarFiles removes the renditions stored under an avatar key. Failures are only
+// logged; they leave unreferenced files behind but do not affect the user.
+func (uc *AuthUseCase) deleteAvatarFiles(key string) {
+	for _, size := range avatarSizes {
+		if err := uc.fileStorage.Delete(avatarFileKey(key, size)); err != nil {
+			log.Printf("Failed to delete avatar file %s: %v", avatarFileKey(key, size), err)
+		}
+	}
+}
+
+// avatarFileKey returns the storage key of one avatar rendition
+func avatarFileKey(key string, size int) string {
+	return key + "/" + strconv.Itoa(size) + ".jpg"
+}
@@ -0,0 +1,59 @@
+package usecases
+
+import (
+	"fmt"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// ChangePassword replaces the password of an authenticated user after checking the current one.
+// When revokeOtherSessions is set, every existing session is revoked and a new one is started
+// for the calling client so that it stays signed in; otherwise the returned tokens are nil.
+func (uc *AuthUseCase) ChangePassword(userID uuid.UUID, currentPassword, newPassword string, revokeOtherSessions bool, client ClientInfo) (*AuthTokens, error) {
+	// Find user
+	user, err := uc.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify current password
+	match, err := uc.passwordHasher.Verify(user.PasswordHash, currentPassword)
+	if err != nil {
+		return nil, err
+	}
+	if !match {
+		return nil, domain.ErrIncorrectPassword
+	}
+	if currentPassword == newPassword {
+		return nil, domain.ErrPasswordUnchanged
+	}
+	if err := uc.checkPassword(newPassword, user.Username, user.Email); err != nil {
+		return nil, err
+	}
+
+	// Hash new password
+	hashedPassword, err := uc.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.PasswordHash = hashedPassword
+
+	metadata := map[string]string{"revoked_other_sessions": strconv.FormatBool(revokeOtherSessions)}
+	if !revokeOtherSessions {
+		if err := uc.userRepository.Update(user); err != nil {
+			return nil, err
+		}
+		uc.recordEvent(entities.AuthEventPasswordChanged, user.ID, client, metadata)
+		return nil, nil
+	}
+
+	// Save the password, sign out every device and re-issue tokens for this one
+	if err := uc.revokeAllTokens(user); err != nil {
+		return nil, err
+	}
+	uc.recordEvent(entities.AuthEventPasswordChanged, user.ID, client, metadata)
+	return uc.startSession(user, client)
+}
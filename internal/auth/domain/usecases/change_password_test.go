@@ -0,0 +1,41 @@
+package usecases
+
+import (
+	"errors"
+	"musicfy/internal/auth/domain"
+	"testing"
+)
+
+func TestChangePasswordPersonalAccessTokens(t *testing.T) {
+	tests := []struct {
+		name                string
+		revokeOtherSessions bool
+		wantErr             error
+	}{
+		{name: "revoking other sessions", revokeOtherSessions: true, wantErr: domain.ErrTokenRevoked},
+		{name: "keeping other sessions", revokeOtherSessions: false, wantErr: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, user := newAuthTestUseCase(t)
+			value := tc.addPersonalAccessToken(user.ID)
+
+			tokens, err := tc.ChangePassword(user.ID, "Old-password-1", "New-password-2", tt.revokeOtherSessions, ClientInfo{})
+			if err != nil {
+				t.Fatalf("ChangePassword: %v", err)
+			}
+			if (tokens != nil) != tt.revokeOtherSessions {
+				t.Errorf("tokens = %v, want tokens only when revoking other sessions", tokens)
+			}
+
+			if _, err := tc.authenticatePersonalAccessToken(value); !errors.Is(err, tt.wantErr) {
+				t.Errorf("token after password change: got %v, want %v", err, tt.wantErr)
+			}
+			stored, _ := tc.users.FindByID(user.ID)
+			if stored.PasswordHash != "hashed:New-password-2" {
+				t.Errorf("password hash = %q, want the new password", stored.PasswordHash)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package usecases
+
+// ClientInfo describes the client a request originates from
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+	// DeviceName is an optional name the client gives itself, shown in the session list
+	DeviceName string
+}
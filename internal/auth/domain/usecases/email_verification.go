@@ -0,0 +1,105 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"net/url"
+	"time"
+)
+
+// VerifyEmail confirms the email address a verification token was issued for
+func (uc *AuthUseCase) VerifyEmail(token string) error {
+	// Find stored token
+	stored, err := uc.emailVerificationRepository.FindByHash(hashOpaqueToken(token))
+	if err != nil {
+		return err
+	}
+	if stored == nil || !stored.IsUsable() {
+		return domain.ErrInvalidVerification
+	}
+
+	// Find token owner; the token only verifies the address it was sent to
+	user, err := uc.userRepository.FindByID(stored.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil || user.Email != stored.Email {
+		return domain.ErrInvalidVerification
+	}
+
+	// Consume token
+	used, err := uc.emailVerificationRepository.MarkUsed(stored.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return domain.ErrInvalidVerification
+	}
+
+	if user.IsEmailVerified() {
+		return nil
+	}
+
+	// Only the verification time is written, so that concurrent changes to the account are kept
+	return uc.userRepository.SetEmailVerified(user.ID, stored.Email, time.Now())
+}
+
+// ResendVerificationEmail sends a new verification email to an unverified account.
+// Unknown and already verified addresses are ignored and throttled requests dropped, so
+// that callers cannot probe for accounts.
+func (uc *AuthUseCase) ResendVerificationEmail(email string) error {
+	// Find user
+	user, err := uc.userRepository.FindByEmail(entities.NormalizeEmail(email))
+	if err != nil {
+		return err
+	}
+	if user == nil || user.IsEmailVerified() {
+		return nil
+	}
+
+	// Silently throttle verification emails per user
+	now := time.Now()
+	recent, err := uc.emailVerificationRepository.CountCreatedSince(user.ID, now.Add(-uc.settings.VerificationResendInterval))
+	if err != nil {
+		return err
+	}
+	hourly, err := uc.emailVerificationRepository.CountCreatedSince(user.ID, now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if recent > 0 || hourly >= uc.settings.VerificationMaxPerHour {
+		return nil
+	}
+
+	if err := uc.sendVerificationEmail(user); err != nil {
+		log.Printf("Failed to send verification email to user %s: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// sendVerificationEmail issues a verification token for the user's current address and mails it
+func (uc *AuthUseCase) sendVerificationEmail(user *entities.User) error {
+	// Generate and store token
+	token, tokenHash, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+	stored := entities.NewEmailVerificationToken(user.ID, user.Email, tokenHash, uc.settings.EmailVerificationExpiry)
+	if err := uc.emailVerificationRepository.Create(stored); err != nil {
+		return err
+	}
+
+	// Send email
+	link := uc.settings.PublicURL + "/api/v1/auth/verify-email?token=" + url.QueryEscape(token)
+	return uc.mailer.Send(EmailMessage{
+		To:      user.Email,
+		Subject: "Verify your Musicfy email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %s. If you did not create a Musicfy account, you can ignore this email.\n",
+			user.FirstName, link, formatExpiry(uc.settings.EmailVerificationExpiry),
+		),
+	})
+}
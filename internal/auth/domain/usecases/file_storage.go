@@ -0,0 +1,13 @@
+package usecases
+
+// FileStorage defines the interface for storing uploaded files, addressed by slash-separated keys
+type FileStorage interface {
+	// Put stores content under a key, replacing any previous content
+	Put(key string, content []byte, contentType string) error
+
+	// Delete removes the content stored under a key; missing keys are not an error
+	Delete(key string) error
+
+	// URL returns the public URL of the content stored under a key
+	URL(key string) string
+}
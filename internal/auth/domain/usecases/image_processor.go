@@ -0,0 +1,19 @@
+package usecases
+
+// ImageProcessor defines the interface for inspecting and resizing uploaded images
+type ImageProcessor interface {
+	// Inspect sniffs the content type of an image and reads its dimensions without decoding
+	// it. It returns domain.ErrUnsupportedImage for content that is not a supported image.
+	Inspect(content []byte) (ImageInfo, error)
+
+	// SquareThumbnails crops an image to a centred square and scales it to each of the edge
+	// lengths, encoded as JPEG
+	SquareThumbnails(content []byte, sizes []int) (map[int][]byte, error)
+}
+
+// ImageInfo describes an uploaded image
+type ImageInfo struct {
+	ContentType string
+	Width       int
+	Height      int
+}
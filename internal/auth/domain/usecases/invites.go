@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RegistrationMode decides who may register
+type RegistrationMode string
+
+// Registration modes
+const (
+	// RegistrationOpen lets anyone register; an invite code is optional
+	RegistrationOpen RegistrationMode = "open"
+	// RegistrationInviteOnly requires a valid invite code
+	RegistrationInviteOnly RegistrationMode = "invite_only"
+	// RegistrationClosed refuses every registration
+	RegistrationClosed RegistrationMode = "closed"
+)
+
+// IsValid reports whether the mode is a known registration mode
+func (m RegistrationMode) IsValid() bool {
+	switch m {
+	case RegistrationOpen, RegistrationInviteOnly, RegistrationClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Page sizes of invite listings
+const (
+	defaultInviteListLimit = 20
+	maxInviteListLimit     = 100
+)
+
+// inviteCodePrefixLength is the number of leading code characters kept to identify an invite
+const inviteCodePrefixLength = 4
+
+// InviteDetails describes an invite and the users who registered with it
+type InviteDetails struct {
+	Invite      *entities.Invite
+	Redemptions []*entities.InviteRedemption
+}
+
+// CreateInvite mints an invite code usable maxUses times; expiresAt may be nil. The code is
+// only returned here, since just its hash is stored.
+func (uc *AdminUseCase) CreateInvite(adminID uuid.UUID, note string, maxUses int, expiresAt *time.Time, client ClientInfo) (*entities.Invite, string, error) {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", domain.ErrInvalidExpiry
+	}
+
+	// Generate and store code
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, "", err
+	}
+	invite := entities.NewInvite(hashInviteCode(code), code[:inviteCodePrefixLength], strings.TrimSpace(note), maxUses, expiresAt, adminID)
+	if err := uc.inviteRepository.Create(invite); err != nil {
+		return nil, "", err
+	}
+
+	uc.authUseCase.recordEvent(entities.AuthEventInviteCreated, adminID, client, map[string]string{
+		"invite_id": invite.ID.String(),
+	})
+	return invite, code, nil
+}
+
+// ListInvites returns a page of invites, newest first
+func (uc *AdminUseCase) ListInvites(limit, offset int) ([]*entities.Invite, error) {
+	if limit <= 0 {
+		limit = defaultInviteListLimit
+	}
+	if limit > maxInviteListLimit {
+		limit = maxInviteListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return uc.inviteRepository.List(limit, offset)
+}
+
+// GetInvite returns an invite with its redemptions
+func (uc *AdminUseCase) GetInvite(id uuid.UUID) (*InviteDetails, error) {
+	invite, err := uc.inviteRepository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if invite == nil {
+		return nil, domain.ErrInviteNotFound
+	}
+
+	redemptions, err := uc.inviteRepository.ListRedemptions(invite.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InviteDetails{Invite: invite, Redemptions: redemptions}, nil
+}
+
+// checkRegistrationAllowed applies the registration mode and returns the invite to redeem,
+// if a code was given
+func (uc *AuthUseCase) checkRegistrationAllowed(inviteCode string) (*entities.Invite, error) {
+	switch {
+	case uc.settings.RegistrationMode == RegistrationClosed:
+		return nil, domain.ErrRegistrationClosed
+	case strings.TrimSpace(inviteCode) == "":
+		if uc.settings.RegistrationMode == RegistrationInviteOnly {
+			return nil, domain.ErrInviteRequired
+		}
+		return nil, nil
+	}
+
+	invite, err := uc.inviteRepository.FindByCodeHash(hashInviteCode(inviteCode))
+	if err != nil {
+		return nil, err
+	}
+	if invite == nil || !invite.IsUsable() {
+		return nil, domain.ErrInvalidInvite
+	}
+	return invite, nil
+}
+
+// generateInviteCode creates a random invite code in groups of four characters, easy to
+// read out and type
+func generateInviteCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	raw := recoveryCodeEncoding.EncodeToString(b)
+	groups := make([]string, 0, len(raw)/4)
+	for i := 0; i < len(raw); i += 4 {
+		groups = append(groups, raw[i:i+4])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// hashInviteCode normalises an invite code as typed by a user and hashes it
+func hashInviteCode(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashOpaqueToken(normalized)
+}
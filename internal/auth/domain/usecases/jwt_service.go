@@ -1,18 +1,65 @@
 package usecases
 
-import "github.com/google/uuid"
+import (
+	"crypto"
+	"musicfy/internal/auth/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // JWTService defines the interface for JWT operations
 type JWTService interface {
-	// GenerateToken creates a new JWT token for a user
-	GenerateToken(userID uuid.UUID, username string) (string, error)
+	// GenerateToken creates a new JWT token for a user, embedding their roles and session
+	GenerateToken(user *entities.User, sessionID uuid.UUID) (string, error)
 
-	// ValidateToken validates a JWT token and returns the claims
+	// ValidateToken validates a JWT access token and returns the claims. Rejected tokens
+	// yield the domain token error describing why, such as ErrTokenExpired.
 	ValidateToken(tokenString string) (*JWTClaims, error)
+
+	// GenerateChallengeToken creates a short-lived token proving that a user passed the
+	// password step of a login that still requires a second factor
+	GenerateChallengeToken(userID uuid.UUID) (string, error)
+
+	// ValidateChallengeToken validates a challenge token and returns its claims
+	ValidateChallengeToken(tokenString string) (*JWTClaims, error)
+
+	// ChallengeTokenExpiry returns the lifetime of challenge tokens
+	ChallengeTokenExpiry() time.Duration
+
+	// GenerateRefreshToken creates a new opaque refresh token
+	GenerateRefreshToken() (string, error)
+
+	// HashRefreshToken returns the value under which a refresh token is stored
+	HashRefreshToken(token string) string
+
+	// AccessTokenExpiry returns the lifetime of access tokens
+	AccessTokenExpiry() time.Duration
+
+	// RefreshTokenExpiry returns the lifetime of refresh tokens
+	RefreshTokenExpiry() time.Duration
+
+	// PublicKeys returns the keys other services can verify tokens with; it is empty when
+	// tokens are signed with a shared secret
+	PublicKeys() []PublicKey
+}
+
+// PublicKey is a published token verification key
+type PublicKey struct {
+	KeyID     string
+	Algorithm string
+	Key       crypto.PublicKey
 }
 
-// JWTClaims represents the claims in a JWT token
+// JWTClaims represents the claims in a JWT token. Requests authenticated with a personal
+// access token get the same claims, with Scopes set and no SessionID.
 type JWTClaims struct {
-	UserID   uuid.UUID
-	Username string
+	TokenID   uuid.UUID
+	UserID    uuid.UUID
+	SessionID uuid.UUID
+	Username  string
+	Roles     []entities.Role
+	Scopes    []entities.Permission
+	IssuedAt  time.Time
+	ExpiresAt time.Time
 }
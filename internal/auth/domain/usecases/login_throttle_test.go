@@ -0,0 +1,45 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutPolicyLockDuration(t *testing.T) {
+	policy := LockoutPolicy{
+		BaseDuration: time.Minute,
+		MaxDuration:  time.Hour,
+	}
+
+	tests := []struct {
+		name        string
+		failures    int
+		maxAttempts int
+		want        time.Duration
+	}{
+		{name: "below the limit", failures: 4, maxAttempts: 5, want: 0},
+		{name: "at the limit", failures: 5, maxAttempts: 5, want: time.Minute},
+		{name: "one over the limit", failures: 6, maxAttempts: 5, want: 2 * time.Minute},
+		{name: "doubles per failure", failures: 9, maxAttempts: 5, want: 16 * time.Minute},
+		{name: "last step under the cap", failures: 10, maxAttempts: 5, want: 32 * time.Minute},
+		{name: "capped", failures: 11, maxAttempts: 5, want: time.Hour},
+		{name: "stays capped without overflowing", failures: 1000, maxAttempts: 5, want: time.Hour},
+		{name: "disabled limit", failures: 100, maxAttempts: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.lockDuration(tt.failures, tt.maxAttempts); got != tt.want {
+				t.Errorf("lockDuration(%d, %d) = %v, want %v", tt.failures, tt.maxAttempts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockoutPolicyLockDurationBaseOverCap(t *testing.T) {
+	policy := LockoutPolicy{BaseDuration: 2 * time.Hour, MaxDuration: time.Hour}
+
+	if got := policy.lockDuration(3, 3); got != time.Hour {
+		t.Errorf("lockDuration() = %v, want %v", got, time.Hour)
+	}
+}
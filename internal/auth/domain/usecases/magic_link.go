@@ -0,0 +1,178 @@
+package usecases
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"net/url"
+	"time"
+)
+
+// RequestMagicLink emails a single-use login link to the account with the given address and
+// returns the device binding the requesting client must present together with the link.
+// A binding is returned whether or not the account exists so that callers cannot probe for
+// accounts; per-account throttling and delivery problems are silent for the same reason.
+func (uc *AuthUseCase) RequestMagicLink(email string, client ClientInfo) (string, error) {
+	// Throttle requests per client IP
+	if err := uc.checkMagicLinkRate(client); err != nil {
+		return "", err
+	}
+
+	// Generate the device binding
+	binding, bindingHash, err := generateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	// Find user; accounts that cannot sign in get no email
+	user, err := uc.userRepository.FindByEmail(entities.NormalizeEmail(email))
+	if err != nil {
+		return "", err
+	}
+	if user == nil || checkAccountStatus(user) != nil {
+		return binding, nil
+	}
+
+	// Silently throttle emails per user
+	sent, err := uc.magicLinkRepository.CountCreatedSince(user.ID, time.Now().Add(-time.Hour))
+	if err != nil {
+		return "", err
+	}
+	if sent >= uc.settings.MagicLinkMaxPerHour {
+		return binding, nil
+	}
+
+	if err := uc.sendMagicLinkEmail(user, bindingHash, client); err != nil {
+		log.Printf("Failed to send magic link email to user %s: %v", user.ID, err)
+	}
+
+	return binding, nil
+}
+
+// CompleteMagicLinkLogin exchanges a magic link and its device binding for the same result
+// as LoginUser: tokens, or a challenge when two-factor authentication is enabled. Opening
+// the link proves ownership of the email address, which is marked verified.
+func (uc *AuthUseCase) CompleteMagicLinkLogin(token, deviceBinding string, client ClientInfo) (*LoginResult, error) {
+	// Refuse attempts from blocked clients
+	if err := uc.checkLoginAllowed(nil, client); err != nil {
+		return nil, err
+	}
+
+	// Find stored token; guesses count as failed login attempts
+	stored, err := uc.magicLinkRepository.FindByHash(hashOpaqueToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil || !stored.IsUsable() {
+		if err := uc.recordFailedLogin(nil, client); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidMagicLink
+	}
+
+	// Find token owner
+	user, err := uc.userRepository.FindByID(stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrInvalidMagicLink
+	}
+
+	// The link only works on the device that requested it. Only the client IP is penalised:
+	// whoever got hold of a forwarded or intercepted link must not lock out the owner.
+	if subtle.ConstantTimeCompare([]byte(hashOpaqueToken(deviceBinding)), []byte(stored.DeviceHash)) != 1 {
+		uc.recordFailedLoginEvent(user, user.Email, loginFailureDeviceMismatch, client)
+		if err := uc.recordFailedLogin(nil, client); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrInvalidMagicLink
+	}
+
+	// Refuse locked, suspended and reset-pending accounts
+	if err := uc.checkLoginAllowed(user, client); err != nil {
+		if isLockoutError(err) {
+			uc.recordFailedLoginEvent(user, user.Email, loginFailureBlocked, client)
+		}
+		return nil, err
+	}
+	if err := checkAccountStatus(user); err != nil {
+		uc.recordFailedLoginEvent(user, user.Email, loginFailureAccountStatus, client)
+		return nil, err
+	}
+
+	// Consume token
+	used, err := uc.magicLinkRepository.MarkUsed(stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, domain.ErrInvalidMagicLink
+	}
+
+	// The link was delivered to the address, which proves the user owns it; only the
+	// verification time is written, so that concurrent changes to the account are kept
+	if !user.IsEmailVerified() {
+		now := time.Now()
+		if err := uc.userRepository.SetEmailVerified(user.ID, user.Email, now); err != nil {
+			return nil, err
+		}
+		user.EmailVerifiedAt = &now
+	}
+
+	return uc.completeFirstFactor(user, client)
+}
+
+// checkMagicLinkRate counts a magic link request from the client IP and rejects it once the
+// IP is over the hourly limit. Every request counts, whether or not the address belongs to
+// an account, so that the limit reveals nothing about which addresses are registered.
+func (uc *AuthUseCase) checkMagicLinkRate(client ClientInfo) error {
+	if client.IPAddress == "" || uc.settings.MagicLinkIPMaxPerHour <= 0 {
+		return nil
+	}
+
+	key := entities.MagicLinkThrottleKey(client.IPAddress)
+	if err := uc.checkLoginThrottle(key, domain.ErrTooManyRequests); err != nil {
+		return err
+	}
+
+	// Requests are counted like failed logins; the count restarts after an hour without any
+	now := time.Now()
+	throttle, err := uc.loginThrottleRepository.RecordFailure(key, now.Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if throttle.Failures > uc.settings.MagicLinkIPMaxPerHour {
+		if err := uc.loginThrottleRepository.Lock(key, now.Add(time.Hour)); err != nil {
+			return err
+		}
+		return domain.ErrTooManyRequests
+	}
+	return nil
+}
+
+// sendMagicLinkEmail issues a magic link bound to the device hash and mails it
+func (uc *AuthUseCase) sendMagicLinkEmail(user *entities.User, deviceHash string, client ClientInfo) error {
+	// Generate and store token
+	token, tokenHash, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+	stored := entities.NewMagicLinkToken(user.ID, tokenHash, deviceHash, client.IPAddress, uc.settings.MagicLinkExpiry)
+	if err := uc.magicLinkRepository.Create(stored); err != nil {
+		return err
+	}
+
+	// Send email
+	link := uc.settings.MagicLinkURL + "?token=" + url.QueryEscape(token)
+	return uc.mailer.Send(EmailMessage{
+		To:      user.Email,
+		Subject: "Your Musicfy login link",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nOpen the link below on the device where you asked to log in:\n\n%s\n\nThe link expires in %s and can be used once. If you did not try to log in, you can ignore this email.\n",
+			user.FirstName, link, formatExpiry(uc.settings.MagicLinkExpiry),
+		),
+	})
+}
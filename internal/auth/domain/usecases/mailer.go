@@ -0,0 +1,39 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+)
+
+// Mailer defines the interface for sending emails
+type Mailer interface {
+	// Send delivers an email message
+	Send(message EmailMessage) error
+}
+
+// EmailMessage represents a plain text email
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// formatExpiry renders a token lifetime for use in email bodies
+func formatExpiry(d time.Duration) string {
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		return pluralize(int(d/time.Hour), "hour")
+	case d >= time.Minute:
+		return pluralize(int(d/time.Minute), "minute")
+	default:
+		return pluralize(int(d/time.Second), "second")
+	}
+}
+
+// pluralize formats a count with a singular or plural unit
+func pluralize(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,14 @@
+package usecases
+
+// PasswordHasher defines the interface for hashing and verifying passwords. Hashes are
+// self-describing strings that carry their algorithm and parameters.
+type PasswordHasher interface {
+	// Hash hashes a password with the configured algorithm and parameters
+	Hash(password string) (string, error)
+
+	// Verify reports whether the password matches the hash; an error means the hash could not be read
+	Verify(hash, password string) (bool, error)
+
+	// NeedsRehash reports whether the hash was made with another algorithm or outdated parameters
+	NeedsRehash(hash string) bool
+}
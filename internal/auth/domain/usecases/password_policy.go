@@ -0,0 +1,152 @@
+package usecases
+
+import (
+	"fmt"
+	"musicfy/internal/auth/domain"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Password policy violation codes
+const (
+	PasswordTooShort          = "too_short"
+	PasswordTooLong           = "too_long"
+	PasswordTooFewClasses     = "too_few_character_classes"
+	PasswordSimilarToUsername = "similar_to_username"
+	PasswordSimilarToEmail    = "similar_to_email"
+	PasswordBreached          = "breached"
+)
+
+// minSimilarityLength is the shortest username or email part checked for similarity, so
+// that very short names do not rule out most passwords
+const minSimilarityLength = 3
+
+// BreachedPasswordChecker defines the interface for looking up passwords known from data breaches
+type BreachedPasswordChecker interface {
+	// IsBreached reports whether the password appears in a known breach
+	IsBreached(password string) (bool, error)
+}
+
+// PasswordPolicy configures which passwords are accepted when one is chosen
+type PasswordPolicy struct {
+	// MinLength and MaxLength bound the number of characters
+	MinLength int
+	MaxLength int
+
+	// MaxBytes caps the UTF-8 encoded length for hashers that only read so many bytes; 0 means no cap
+	MaxBytes int
+
+	// MinCharacterClasses is how many of lowercase, uppercase, digits and symbols must occur
+	MinCharacterClasses int
+
+	// RejectSimilarToIdentity refuses passwords containing the username or email, or contained in them
+	RejectSimilarToIdentity bool
+}
+
+// checkPassword validates a new password against the policy and the breached password list.
+// It returns a *domain.PasswordPolicyError listing every violation.
+func (uc *AuthUseCase) checkPassword(password, username, email string) error {
+	policy := uc.settings.PasswordPolicy
+	var violations []domain.PasswordViolation
+
+	// Length
+	length := utf8.RuneCountInString(password)
+	if length < policy.MinLength {
+		violations = append(violations, domain.PasswordViolation{
+			Code:    PasswordTooShort,
+			Message: fmt.Sprintf("must be at least %d characters long", policy.MinLength),
+		})
+	}
+	if policy.MaxLength > 0 && length > policy.MaxLength {
+		violations = append(violations, domain.PasswordViolation{
+			Code:    PasswordTooLong,
+			Message: fmt.Sprintf("must be at most %d characters long", policy.MaxLength),
+		})
+	} else if policy.MaxBytes > 0 && len(password) > policy.MaxBytes {
+		violations = append(violations, domain.PasswordViolation{
+			Code:    PasswordTooLong,
+			Message: fmt.Sprintf("must be at most %d bytes long", policy.MaxBytes),
+		})
+	}
+
+	// Character classes
+	if classes := characterClasses(password); classes < policy.MinCharacterClasses {
+		violations = append(violations, domain.PasswordViolation{
+			Code:    PasswordTooFewClasses,
+			Message: fmt.Sprintf("must mix at least %d of lowercase letters, uppercase letters, digits and symbols", policy.MinCharacterClasses),
+		})
+	}
+
+	// Similarity to the account's identity
+	if policy.RejectSimilarToIdentity {
+		if isSimilar(password, username) {
+			violations = append(violations, domain.PasswordViolation{
+				Code:    PasswordSimilarToUsername,
+				Message: "must not contain or be part of the username",
+			})
+		}
+		localPart, _, _ := strings.Cut(email, "@")
+		if isSimilar(password, localPart) || isSimilar(password, email) {
+			violations = append(violations, domain.PasswordViolation{
+				Code:    PasswordSimilarToEmail,
+				Message: "must not contain or be part of the email address",
+			})
+		}
+	}
+
+	// Known breaches
+	breached, err := uc.breachedPasswordChecker.IsBreached(password)
+	if err != nil {
+		return err
+	}
+	if breached {
+		violations = append(violations, domain.PasswordViolation{
+			Code:    PasswordBreached,
+			Message: "appears in a known data breach; choose a password you have not used elsewhere",
+		})
+	}
+
+	if len(violations) > 0 {
+		return &domain.PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// characterClasses counts which of lowercase, uppercase, digits and symbols occur in s
+func characterClasses(s string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+// isSimilar reports whether the password contains identity or is contained in it,
+// ignoring case and anything but letters and digits
+func isSimilar(password, identity string) bool {
+	p, id := alphanumeric(password), alphanumeric(identity)
+	if len(id) < minSimilarityLength || p == "" {
+		return false
+	}
+	return strings.Contains(p, id) || strings.Contains(id, p)
+}
+
+// alphanumeric lowercases s and drops everything but letters and digits
+func alphanumeric(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
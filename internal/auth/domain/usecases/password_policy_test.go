@@ -0,0 +1,131 @@
+package usecases
+
+import (
+	"errors"
+	"musicfy/internal/auth/domain"
+	"reflect"
+	"testing"
+)
+
+// listedPasswords is a BreachedPasswordChecker reporting a fixed set of passwords
+type listedPasswords map[string]bool
+
+func (l listedPasswords) IsBreached(password string) (bool, error) {
+	return l[password], nil
+}
+
+// failingChecker is a BreachedPasswordChecker whose lookups fail
+type failingChecker struct{}
+
+func (failingChecker) IsBreached(password string) (bool, error) {
+	return false, errors.New("lookup failed")
+}
+
+func TestCheckPassword(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:               8,
+		MaxLength:               20,
+		MaxBytes:                24,
+		MinCharacterClasses:     2,
+		RejectSimilarToIdentity: true,
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		username string
+		email    string
+		want     []string
+	}{
+		{name: "acceptable", password: "Tulip-Harbor-93", want: nil},
+		{name: "too short", password: "Ab1", want: []string{PasswordTooShort}},
+		{name: "exactly the minimum", password: "abcdefg1", want: nil},
+		{name: "too long", password: "abcdefghij1234567890x", want: []string{PasswordTooLong}},
+		{name: "length counts characters, not bytes", password: "ééééééé1", want: nil},
+		{name: "too many bytes", password: "éééééééééééééééééé1", want: []string{PasswordTooLong}},
+		{name: "single character class", password: "abcdefghij", want: []string{PasswordTooFewClasses}},
+		{name: "symbols count as a class", password: "abcdefgh!", want: nil},
+		{name: "contains the username", password: "xx-Johndoe-99", username: "johndoe", want: []string{PasswordSimilarToUsername}},
+		{name: "part of the username", password: "Musicfan1", username: "musicfan1987", want: []string{PasswordSimilarToUsername}},
+		{name: "username ignoring punctuation", password: "john.doe.2024", username: "john_doe", want: []string{PasswordSimilarToUsername}},
+		{name: "short username is not checked", password: "al-fresco-9", username: "al", want: nil},
+		{name: "contains the email local part", password: "Jane.Smith!1", email: "jane.smith@example.com", want: []string{PasswordSimilarToEmail}},
+		{name: "contains the whole email", password: "x@jane@example.com", email: "jane@example.com", want: []string{PasswordSimilarToEmail}},
+		{name: "unrelated to identity", password: "Tulip-Harbor-93", username: "johndoe", email: "jane@example.com", want: nil},
+		{name: "breached", password: "Password1", want: []string{PasswordBreached}},
+		{name: "every violation", password: "doe", username: "doe", email: "doe@example.com", want: []string{PasswordTooShort, PasswordTooFewClasses, PasswordSimilarToUsername, PasswordSimilarToEmail}},
+	}
+
+	uc := &AuthUseCase{
+		breachedPasswordChecker: listedPasswords{"Password1": true},
+		settings:                AuthSettings{PasswordPolicy: policy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := uc.checkPassword(tt.password, tt.username, tt.email)
+
+			var got []string
+			var policyErr *domain.PasswordPolicyError
+			if errors.As(err, &policyErr) {
+				for _, violation := range policyErr.Violations {
+					got = append(got, violation.Code)
+				}
+				if !errors.Is(err, domain.ErrWeakPassword) {
+					t.Error("policy error does not match ErrWeakPassword")
+				}
+			} else if err != nil {
+				t.Fatalf("checkPassword() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkPassword() violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPasswordIdentityCheckDisabled(t *testing.T) {
+	uc := &AuthUseCase{
+		breachedPasswordChecker: listedPasswords{},
+		settings:                AuthSettings{PasswordPolicy: PasswordPolicy{MinLength: 8, MinCharacterClasses: 1}},
+	}
+
+	if err := uc.checkPassword("johndoe123", "johndoe", "johndoe@example.com"); err != nil {
+		t.Errorf("checkPassword() = %v, want nil", err)
+	}
+}
+
+func TestCheckPasswordBreachLookupFailure(t *testing.T) {
+	uc := &AuthUseCase{
+		breachedPasswordChecker: failingChecker{},
+		settings:                AuthSettings{PasswordPolicy: PasswordPolicy{MinLength: 8}},
+	}
+
+	err := uc.checkPassword("Tulip-Harbor-93", "", "")
+	if err == nil || errors.Is(err, domain.ErrWeakPassword) {
+		t.Errorf("checkPassword() = %v, want the lookup error", err)
+	}
+}
+
+func TestCharacterClasses(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"abc", 1},
+		{"abcABC", 2},
+		{"abcABC123", 3},
+		{"abcABC123!", 4},
+		{"ÄÖÜäöü", 2},
+		{"٣٤٥", 1},
+		{"  ", 1},
+	}
+
+	for _, tt := range tests {
+		if got := characterClasses(tt.s); got != tt.want {
+			t.Errorf("characterClasses(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"net/url"
+	"time"
+)
+
+// maxPasswordResetsPerHour caps the number of reset emails sent to one account
+const maxPasswordResetsPerHour = 5
+
+// ForgotPassword emails a password reset link to the account with the given address.
+// It behaves the same whether or not the account exists so that callers cannot probe for
+// accounts; delivery problems are logged rather than returned for the same reason.
+func (uc *AuthUseCase) ForgotPassword(email string) error {
+	// Find user
+	user, err := uc.userRepository.FindByEmail(entities.NormalizeEmail(email))
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	// Silently throttle reset emails per user
+	sent, err := uc.passwordResetRepository.CountCreatedSince(user.ID, time.Now().Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if sent >= maxPasswordResetsPerHour {
+		return nil
+	}
+
+	if err := uc.sendPasswordResetEmail(user); err != nil {
+		log.Printf("Failed to send password reset email to user %s: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token and signs the user out everywhere
+func (uc *AuthUseCase) ResetPassword(token, newPassword string, client ClientInfo) error {
+	// Find stored token
+	stored, err := uc.passwordResetRepository.FindByHash(hashOpaqueToken(token))
+	if err != nil {
+		return err
+	}
+	if stored == nil || !stored.IsUsable() {
+		return domain.ErrInvalidResetToken
+	}
+
+	// Find token owner
+	user, err := uc.userRepository.FindByID(stored.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return domain.ErrInvalidResetToken
+	}
+
+	// Check password against the policy before the token is spent
+	if err := uc.checkPassword(newPassword, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	// Consume token
+	used, err := uc.passwordResetRepository.MarkUsed(stored.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return domain.ErrInvalidResetToken
+	}
+
+	// Hash new password
+	hashedPassword, err := uc.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+	user.PasswordHash = hashedPassword
+
+	// A reset forced by an admin is complete; suspensions stay in place
+	if user.Status == entities.UserStatusPasswordResetRequired {
+		user.Status = entities.UserStatusActive
+	}
+
+	// Save the password and invalidate existing sessions and personal access tokens,
+	// which may have been created by whoever knew the old password
+	if err := uc.revokeAllTokens(user); err != nil {
+		return err
+	}
+
+	// Other reset links sent before this one must not work anymore
+	if err := uc.passwordResetRepository.MarkAllUsedForUser(user.ID); err != nil {
+		return err
+	}
+
+	uc.recordEvent(entities.AuthEventPasswordReset, user.ID, client, nil)
+	return nil
+}
+
+// sendPasswordResetEmail issues a reset token for the user and mails it
+func (uc *AuthUseCase) sendPasswordResetEmail(user *entities.User) error {
+	// Generate and store token
+	token, tokenHash, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+	stored := entities.NewPasswordResetToken(user.ID, tokenHash, uc.settings.PasswordResetExpiry)
+	if err := uc.passwordResetRepository.Create(stored); err != nil {
+		return err
+	}
+
+	// Send email
+	link := uc.settings.PasswordResetURL + "?token=" + url.QueryEscape(token)
+	return uc.mailer.Send(EmailMessage{
+		To:      user.Email,
+		Subject: "Reset your Musicfy password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n\n%s\n\nThe link expires in %s and can be used once. If you did not request a password reset, you can ignore this email.\n",
+			user.FirstName, link, formatExpiry(uc.settings.PasswordResetExpiry),
+		),
+	})
+}
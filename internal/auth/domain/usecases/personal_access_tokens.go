@@ -0,0 +1,142 @@
+package usecases
+
+import (
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// personalAccessTokenVisibleLength is how much of a token is stored in clear text, so that
+// users can recognise their tokens in the list
+const personalAccessTokenVisibleLength = len(entities.PersonalAccessTokenPrefix) + 8
+
+// personalAccessTokenTouchInterval limits how often token use is written to the database
+const personalAccessTokenTouchInterval = time.Minute
+
+// CreatePersonalAccessToken mints a token limited to the given scopes, each of which must be
+// granted by the user's roles. A nil expiresAt creates a token that does not expire. The token
+// value is only returned here; afterwards only its prefix is known.
+func (uc *AuthUseCase) CreatePersonalAccessToken(userID uuid.UUID, name string, scopes []entities.Permission, expiresAt *time.Time, client ClientInfo) (*entities.PersonalAccessToken, string, error) {
+	// Find user
+	user, err := uc.GetUserByID(userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Scopes can only narrow what the user may do
+	if len(scopes) == 0 {
+		return nil, "", domain.ErrInvalidScope
+	}
+	for _, scope := range scopes {
+		if !scope.IsValid() || !entities.RolesHavePermission(user.Roles, scope) {
+			return nil, "", domain.ErrInvalidScope
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, "", domain.ErrInvalidExpiry
+	}
+
+	// Generate and store token
+	secret, _, err := generateOpaqueToken()
+	if err != nil {
+		return nil, "", err
+	}
+	value := entities.PersonalAccessTokenPrefix + secret
+	token := entities.NewPersonalAccessToken(
+		user.ID,
+		strings.TrimSpace(name),
+		value[:personalAccessTokenVisibleLength],
+		hashOpaqueToken(value),
+		scopes,
+		expiresAt,
+	)
+	if err := uc.personalAccessTokenRepository.Create(token); err != nil {
+		return nil, "", err
+	}
+	scopeNames := make([]string, len(token.Scopes))
+	for i, scope := range token.Scopes {
+		scopeNames[i] = string(scope)
+	}
+	uc.recordEvent(entities.AuthEventPersonalAccessTokenCreated, user.ID, client, map[string]string{
+		"token_id": token.ID.String(),
+		"name":     token.Name,
+		"scopes":   strings.Join(scopeNames, " "),
+	})
+
+	return token, value, nil
+}
+
+// ListPersonalAccessTokens returns the tokens of a user that have not been revoked
+func (uc *AuthUseCase) ListPersonalAccessTokens(userID uuid.UUID) ([]*entities.PersonalAccessToken, error) {
+	return uc.personalAccessTokenRepository.ListByUserID(userID)
+}
+
+// RevokePersonalAccessToken revokes one of the user's tokens
+func (uc *AuthUseCase) RevokePersonalAccessToken(userID, tokenID uuid.UUID, client ClientInfo) error {
+	revoked, err := uc.personalAccessTokenRepository.Revoke(tokenID, userID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return domain.ErrPersonalAccessTokenNotFound
+	}
+
+	uc.recordEvent(entities.AuthEventPersonalAccessTokenRevoked, userID, client, map[string]string{"token_id": tokenID.String()})
+	return nil
+}
+
+// authenticatePersonalAccessToken checks a personal access token and describes it as claims
+// carrying the owner's current roles and the token's scopes
+func (uc *AuthUseCase) authenticatePersonalAccessToken(value string) (*JWTClaims, error) {
+	// Find stored token
+	token, err := uc.personalAccessTokenRepository.FindByHash(hashOpaqueToken(value))
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, domain.ErrInvalidToken
+	}
+	if token.IsRevoked() {
+		return nil, domain.ErrTokenRevoked
+	}
+	if token.IsExpired() {
+		return nil, domain.ErrTokenExpired
+	}
+
+	// Find token owner
+	user, err := uc.userRepository.FindByID(token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrInvalidToken
+	}
+	if err := checkAccountStatus(user); err != nil {
+		return nil, err
+	}
+
+	// Record use
+	now := time.Now()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= personalAccessTokenTouchInterval {
+		if err := uc.personalAccessTokenRepository.MarkUsed(token.ID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	claims := &JWTClaims{
+		TokenID:  token.ID,
+		UserID:   user.ID,
+		Username: user.Username,
+		Roles:    user.Roles,
+		Scopes:   token.Scopes,
+		IssuedAt: token.CreatedAt,
+	}
+	if token.ExpiresAt != nil {
+		claims.ExpiresAt = *token.ExpiresAt
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,151 @@
+package usecases
+
+import (
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// sessionTouchInterval limits how often request activity is written to a session
+const sessionTouchInterval = time.Minute
+
+// Column limits of the sessions table
+const (
+	maxDeviceNameLength = 100
+	maxUserAgentLength  = 512
+)
+
+// ListSessions returns the active sessions of a user, most recently seen first
+func (uc *AuthUseCase) ListSessions(userID uuid.UUID) ([]*entities.Session, error) {
+	return uc.sessionRepository.ListActiveByUserID(userID)
+}
+
+// RevokeSession signs a user out of one of their sessions. Access tokens of the session are
+// rejected from then on and its refresh tokens can no longer be used.
+func (uc *AuthUseCase) RevokeSession(userID, sessionID uuid.UUID, client ClientInfo) error {
+	// Find session; other users' sessions are reported as missing
+	session, err := uc.sessionRepository.FindByID(sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != userID || !session.IsActive() {
+		return domain.ErrSessionNotFound
+	}
+
+	if err := uc.revokeSession(session.ID); err != nil {
+		return err
+	}
+
+	uc.recordEvent(entities.AuthEventSessionRevoked, userID, client, map[string]string{"session_id": session.ID.String()})
+	return nil
+}
+
+// startSession records a new session for the client and issues its first tokens
+func (uc *AuthUseCase) startSession(user *entities.User, client ClientInfo) (*AuthTokens, error) {
+	session := entities.NewSession(
+		user.ID,
+		truncate(deviceName(client), maxDeviceNameLength),
+		truncate(client.UserAgent, maxUserAgentLength),
+		client.IPAddress,
+		uc.jwtService.RefreshTokenExpiry(),
+	)
+	if err := uc.sessionRepository.Create(session); err != nil {
+		return nil, err
+	}
+
+	tokens, _, err := uc.issueTokens(user, session.ID)
+	return tokens, err
+}
+
+// findActiveSession returns the session a token belongs to, or ErrSessionRevoked
+func (uc *AuthUseCase) findActiveSession(sessionID uuid.UUID) (*entities.Session, error) {
+	session, err := uc.sessionRepository.FindByID(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || !session.IsActive() {
+		return nil, domain.ErrSessionRevoked
+	}
+	return session, nil
+}
+
+// touchSession records activity from the client, at most once per sessionTouchInterval
+// unless the session's expiry has to be extended
+func (uc *AuthUseCase) touchSession(session *entities.Session, client ClientInfo, expiresAt time.Time) error {
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) < sessionTouchInterval && !expiresAt.After(session.ExpiresAt) {
+		return nil
+	}
+
+	session.LastSeenAt = now
+	if client.IPAddress != "" {
+		session.IPAddress = client.IPAddress
+	}
+	if client.UserAgent != "" {
+		session.UserAgent = truncate(client.UserAgent, maxUserAgentLength)
+	}
+	if expiresAt.After(session.ExpiresAt) {
+		session.ExpiresAt = expiresAt
+	}
+
+	return uc.sessionRepository.UpdateActivity(session)
+}
+
+// revokeSession revokes a session together with its refresh token family
+func (uc *AuthUseCase) revokeSession(sessionID uuid.UUID) error {
+	if err := uc.sessionRepository.Revoke(sessionID); err != nil {
+		return err
+	}
+	return uc.refreshTokenRepository.RevokeFamily(sessionID)
+}
+
+// deviceName returns the name the client gave itself or one derived from its user agent
+func deviceName(client ClientInfo) string {
+	if name := strings.TrimSpace(client.DeviceName); name != "" {
+		return name
+	}
+
+	ua := client.UserAgent
+	if ua == "" {
+		return "Unknown device"
+	}
+
+	browser := firstMatch(ua, "Unknown browser", [][2]string{
+		{"Edg/", "Edge"}, {"OPR/", "Opera"}, {"Firefox/", "Firefox"}, {"Chrome/", "Chrome"},
+		{"Safari/", "Safari"}, {"okhttp", "Android app"}, {"CFNetwork", "iOS app"}, {"curl/", "curl"},
+	})
+	system := firstMatch(ua, "", [][2]string{
+		{"iPhone", "iPhone"}, {"iPad", "iPad"}, {"Android", "Android"}, {"Windows", "Windows"},
+		{"Mac OS X", "macOS"}, {"Linux", "Linux"},
+	})
+
+	if system == "" {
+		return browser
+	}
+	return browser + " on " + system
+}
+
+// firstMatch returns the name of the first marker found in s, or fallback
+func firstMatch(s, fallback string, markers [][2]string) string {
+	for _, marker := range markers {
+		if strings.Contains(s, marker[0]) {
+			return marker[1]
+		}
+	}
+	return fallback
+}
+
+// truncate shortens s to at most n bytes without splitting a UTF-8 sequence
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
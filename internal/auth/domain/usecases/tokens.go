@@ -0,0 +1,25 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// generateOpaqueToken creates a random URL-safe token and the hash under which it is stored
+func generateOpaqueToken() (token string, tokenHash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashOpaqueToken(token), nil
+}
+
+// hashOpaqueToken returns the SHA-256 hex digest of an opaque token
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,14 @@
+package usecases
+
+// TOTPService defines the interface for RFC 6238 time-based one-time passwords
+type TOTPService interface {
+	// GenerateSecret creates a new random base32-encoded shared secret
+	GenerateSecret() (string, error)
+
+	// ProvisioningURI returns the otpauth:// URI that authenticator apps import
+	ProvisioningURI(secret, accountName string) string
+
+	// ValidateCode checks a code against the current time window, allowing one step of clock drift.
+	// It returns the time step the code belongs to so that reuse can be detected.
+	ValidateCode(secret, code string) (step int64, ok bool)
+}
@@ -0,0 +1,275 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// recoveryCodeCount is the number of recovery codes issued at once
+const recoveryCodeCount = 10
+
+// recoveryCodeEncoding renders recovery codes without easily confused padding characters
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TwoFactorSetup holds what a user needs to add Musicfy to an authenticator app
+type TwoFactorSetup struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+// LoginResult is the outcome of the password step of a login. Either Tokens is set, or the
+// account has two-factor authentication enabled and ChallengeToken must be exchanged at
+// CompleteTwoFactorLogin together with a code.
+type LoginResult struct {
+	Tokens             *AuthTokens
+	ChallengeToken     string
+	ChallengeExpiresIn time.Duration
+}
+
+// SetupTwoFactor starts a TOTP enrollment; it only takes effect once confirmed with a code
+func (uc *AuthUseCase) SetupTwoFactor(userID uuid.UUID) (*TwoFactorSetup, error) {
+	// Find user
+	user, err := uc.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Refuse to replace an active enrollment
+	existing, err := uc.twoFactorRepository.FindByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.IsEnabled() {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	// Generate and store a new secret
+	secret, err := uc.totpService.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.twoFactorRepository.SavePending(entities.NewTwoFactor(user.ID, secret)); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: uc.totpService.ProvisioningURI(secret, user.Email),
+	}, nil
+}
+
+// ConfirmTwoFactor enables a pending enrollment with the first code from the authenticator
+// app and returns a fresh set of recovery codes
+func (uc *AuthUseCase) ConfirmTwoFactor(userID uuid.UUID, code string, client ClientInfo) ([]string, error) {
+	// Find pending enrollment
+	twoFactor, err := uc.twoFactorRepository.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, domain.ErrTwoFactorNotEnabled
+	}
+	if twoFactor.IsEnabled() {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	// Verify code
+	step, ok := uc.totpService.ValidateCode(twoFactor.Secret, code)
+	if !ok {
+		return nil, domain.ErrInvalidTwoFactorCode
+	}
+	if err := uc.twoFactorRepository.Confirm(userID, step); err != nil {
+		return nil, err
+	}
+	uc.recordEvent(entities.AuthEventTwoFactorEnabled, userID, client, nil)
+
+	return uc.replaceRecoveryCodes(userID)
+}
+
+// DisableTwoFactor removes the TOTP enrollment after checking the password and a second factor
+func (uc *AuthUseCase) DisableTwoFactor(userID uuid.UUID, password, code string, client ClientInfo) error {
+	// Find user
+	user, err := uc.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	// Verify password
+	match, err := uc.passwordHasher.Verify(user.PasswordHash, password)
+	if err != nil {
+		return err
+	}
+	if !match {
+		return domain.ErrIncorrectPassword
+	}
+
+	// Verify second factor
+	if err := uc.verifySecondFactor(user.ID, code); err != nil {
+		return err
+	}
+
+	if err := uc.twoFactorRepository.Delete(user.ID); err != nil {
+		return err
+	}
+
+	uc.recordEvent(entities.AuthEventTwoFactorDisabled, user.ID, client, nil)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a TOTP code
+func (uc *AuthUseCase) RegenerateRecoveryCodes(userID uuid.UUID, code string, client ClientInfo) ([]string, error) {
+	// Find enrollment
+	twoFactor, err := uc.twoFactorRepository.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		return nil, domain.ErrTwoFactorNotEnabled
+	}
+
+	// Verify code; recovery codes cannot be used to mint new ones
+	if err := uc.useTOTPCode(twoFactor, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := uc.replaceRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.recordEvent(entities.AuthEventRecoveryCodesRegenerated, userID, client, nil)
+	return codes, nil
+}
+
+// CompleteTwoFactorLogin finishes a login started by LoginUser using the challenge token
+// and either a TOTP code or a recovery code
+func (uc *AuthUseCase) CompleteTwoFactorLogin(challengeToken, code string, client ClientInfo) (*AuthTokens, error) {
+	// Validate challenge token
+	claims, err := uc.jwtService.ValidateChallengeToken(challengeToken)
+	if err != nil {
+		return nil, domain.ErrInvalidChallenge
+	}
+	revoked, err := uc.revokedTokenRepository.IsRevoked(claims.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, domain.ErrInvalidChallenge
+	}
+
+	// Find user
+	user, err := uc.userRepository.FindByID(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, domain.ErrInvalidChallenge
+	}
+	if err := checkAccountStatus(user); err != nil {
+		return nil, err
+	}
+
+	// Guessing codes counts as failed login attempts
+	if err := uc.checkLoginAllowed(user, client); err != nil {
+		if isLockoutError(err) {
+			uc.recordFailedLoginEvent(user, user.Username, loginFailureBlocked, client)
+		}
+		return nil, err
+	}
+	if err := uc.verifySecondFactor(user.ID, code); err != nil {
+		if errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+			uc.recordFailedLoginEvent(user, user.Username, loginFailureInvalidTwoFactorCode, client)
+			if err := uc.recordFailedLogin(user, client); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	// The challenge can only be completed once
+	if err := uc.revokedTokenRepository.Revoke(claims.TokenID, user.ID, claims.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	return uc.completeLogin(user, client)
+}
+
+// verifySecondFactor checks a TOTP code or, failing that, consumes a recovery code
+func (uc *AuthUseCase) verifySecondFactor(userID uuid.UUID, code string) error {
+	// Find enrollment
+	twoFactor, err := uc.twoFactorRepository.FindByUserID(userID)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil || !twoFactor.IsEnabled() {
+		return domain.ErrTwoFactorNotEnabled
+	}
+
+	// Try the code as a TOTP code first
+	code = strings.TrimSpace(code)
+	if err := uc.useTOTPCode(twoFactor, code); err == nil || !errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+		return err
+	}
+
+	// Fall back to recovery codes
+	used, err := uc.twoFactorRepository.UseRecoveryCode(userID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// useTOTPCode validates a TOTP code and makes sure it cannot be replayed
+func (uc *AuthUseCase) useTOTPCode(twoFactor *entities.TwoFactor, code string) error {
+	step, ok := uc.totpService.ValidateCode(twoFactor.Secret, strings.TrimSpace(code))
+	if !ok {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	fresh, err := uc.twoFactorRepository.UseStep(twoFactor.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// replaceRecoveryCodes generates new recovery codes and stores their hashes
+func (uc *AuthUseCase) replaceRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+		codes[i] = raw[:8] + "-" + raw[8:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+
+	if err := uc.twoFactorRepository.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// hashRecoveryCode normalises a recovery code as typed by a user and hashes it
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return hashOpaqueToken(normalized)
+}
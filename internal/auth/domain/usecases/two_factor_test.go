@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"errors"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryTwoFactorRepository keeps one enrollment and its recovery code hashes in memory
+type memoryTwoFactorRepository struct {
+	twoFactor     *entities.TwoFactor
+	recoveryCodes map[string]bool // hash -> used
+}
+
+func (r *memoryTwoFactorRepository) FindByUserID(userID uuid.UUID) (*entities.TwoFactor, error) {
+	return r.twoFactor, nil
+}
+
+func (r *memoryTwoFactorRepository) SavePending(twoFactor *entities.TwoFactor) error {
+	r.twoFactor = twoFactor
+	return nil
+}
+
+func (r *memoryTwoFactorRepository) Confirm(userID uuid.UUID, step int64) error {
+	now := time.Now()
+	r.twoFactor.ConfirmedAt = &now
+	r.twoFactor.LastUsedStep = step
+	return nil
+}
+
+func (r *memoryTwoFactorRepository) UseStep(userID uuid.UUID, step int64) (bool, error) {
+	if step <= r.twoFactor.LastUsedStep {
+		return false, nil
+	}
+	r.twoFactor.LastUsedStep = step
+	return true, nil
+}
+
+func (r *memoryTwoFactorRepository) Delete(userID uuid.UUID) error {
+	r.twoFactor, r.recoveryCodes = nil, nil
+	return nil
+}
+
+func (r *memoryTwoFactorRepository) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	r.recoveryCodes = make(map[string]bool, len(codeHashes))
+	for _, hash := range codeHashes {
+		r.recoveryCodes[hash] = false
+	}
+	return nil
+}
+
+func (r *memoryTwoFactorRepository) UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	used, ok := r.recoveryCodes[codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	r.recoveryCodes[codeHash] = true
+	return true, nil
+}
+
+// fixedTOTPService accepts the codes it was given, each belonging to a time step
+type fixedTOTPService struct {
+	steps map[string]int64
+}
+
+func (s *fixedTOTPService) GenerateSecret() (string, error) { return "SECRET", nil }
+
+func (s *fixedTOTPService) ProvisioningURI(secret, accountName string) string { return "" }
+
+func (s *fixedTOTPService) ValidateCode(secret, code string) (int64, bool) {
+	step, ok := s.steps[code]
+	return step, ok
+}
+
+func newTwoFactorTestUseCase(t *testing.T) (*AuthUseCase, *memoryTwoFactorRepository, uuid.UUID, []string) {
+	t.Helper()
+
+	userID := uuid.New()
+	confirmedAt := time.Now()
+	repo := &memoryTwoFactorRepository{
+		twoFactor: &entities.TwoFactor{UserID: userID, Secret: "SECRET", ConfirmedAt: &confirmedAt, LastUsedStep: 100},
+	}
+	uc := &AuthUseCase{
+		twoFactorRepository: repo,
+		totpService:         &fixedTOTPService{steps: map[string]int64{"111111": 101, "222222": 100, "333333": 102}},
+	}
+
+	codes, err := uc.replaceRecoveryCodes(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return uc, repo, userID, codes
+}
+
+func TestReplaceRecoveryCodes(t *testing.T) {
+	_, repo, _, codes := newTwoFactorTestUseCase(t)
+
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("got %d codes, want %d", len(codes), recoveryCodeCount)
+	}
+
+	format := regexp.MustCompile(`^[a-z2-7]{8}-[a-z2-7]{8}$`)
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if !format.MatchString(code) {
+			t.Errorf("code %q does not match %s", code, format)
+		}
+		if seen[code] {
+			t.Errorf("code %q issued twice", code)
+		}
+		seen[code] = true
+		if _, ok := repo.recoveryCodes[hashRecoveryCode(code)]; !ok {
+			t.Errorf("hash of code %q was not stored", code)
+		}
+		if _, ok := repo.recoveryCodes[code]; ok {
+			t.Errorf("code %q was stored in plain text", code)
+		}
+	}
+}
+
+func TestHashRecoveryCodeNormalisation(t *testing.T) {
+	want := hashRecoveryCode("abcdefgh-ijklmnop")
+
+	for _, typed := range []string{"ABCDEFGH-IJKLMNOP", "abcdefghijklmnop", "abcd efgh ijkl mnop", "AbCdEfGh-IjKl-MnOp"} {
+		if got := hashRecoveryCode(typed); got != want {
+			t.Errorf("hashRecoveryCode(%q) differs from the canonical code", typed)
+		}
+	}
+	if hashRecoveryCode("abcdefgh-ijklmnoq") == want {
+		t.Error("different codes hash alike")
+	}
+}
+
+func TestVerifySecondFactor(t *testing.T) {
+	uc, _, userID, codes := newTwoFactorTestUseCase(t)
+
+	steps := []struct {
+		name string
+		code string
+		err  error
+	}{
+		{name: "fresh TOTP code", code: "111111", err: nil},
+		{name: "replayed TOTP code", code: "111111", err: domain.ErrInvalidTwoFactorCode},
+		{name: "TOTP code of an earlier step", code: "222222", err: domain.ErrInvalidTwoFactorCode},
+		{name: "later TOTP code", code: " 333333 ", err: nil},
+		{name: "recovery code as typed", code: strings.ToUpper(strings.ReplaceAll(codes[0], "-", " ")), err: nil},
+		{name: "used recovery code", code: codes[0], err: domain.ErrInvalidTwoFactorCode},
+		{name: "other recovery code", code: codes[1], err: nil},
+		{name: "unknown code", code: "aaaaaaaa-aaaaaaaa", err: domain.ErrInvalidTwoFactorCode},
+	}
+
+	for _, step := range steps {
+		if err := uc.verifySecondFactor(userID, step.code); !errors.Is(err, step.err) {
+			t.Errorf("%s: verifySecondFactor() = %v, want %v", step.name, err, step.err)
+		}
+	}
+}
+
+func TestVerifySecondFactorRequiresEnrollment(t *testing.T) {
+	uc, repo, userID, codes := newTwoFactorTestUseCase(t)
+	repo.twoFactor.ConfirmedAt = nil
+
+	if err := uc.verifySecondFactor(userID, codes[0]); !errors.Is(err, domain.ErrTwoFactorNotEnabled) {
+		t.Errorf("verifySecondFactor() = %v, want %v", err, domain.ErrTwoFactorNotEnabled)
+	}
+}
@@ -0,0 +1,118 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProfileUpdate holds the profile fields to change; nil fields are left untouched
+type ProfileUpdate struct {
+	FirstName   *string
+	LastName    *string
+	Username    *string
+	Email       *string
+	DateOfBirth *time.Time
+
+	// CurrentPassword confirms a change of the email address, which password resets are sent to
+	CurrentPassword string
+}
+
+// UpdateProfile applies a partial update to the profile of a user.
+// Changing the email address requires the current password, marks the address unverified,
+// sends a new verification email and notifies the previous address.
+// A new date of birth must meet the minimum age, as at registration.
+// Username and email changes are audited. Access tokens issued before a username change
+// keep the old username claim until they expire; the user ID stays the same.
+func (uc *AuthUseCase) UpdateProfile(userID uuid.UUID, update ProfileUpdate, client ClientInfo) (*entities.User, error) {
+	// Find user
+	user, err := uc.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply identity changes; the repository reports a username or email taken by
+	// another user
+	previousUsername, previousEmail := user.Username, user.Email
+	usernameChanged := update.Username != nil && entities.NormalizeUsername(*update.Username) != user.Username
+	if usernameChanged {
+		user.Username = entities.NormalizeUsername(*update.Username)
+		if !entities.IsValidUsername(user.Username) {
+			return nil, domain.ErrInvalidUsername
+		}
+	}
+	emailChanged := update.Email != nil && entities.NormalizeEmail(*update.Email) != user.Email
+	if emailChanged {
+		// Whoever controls the email address can reset the password, so a stolen session
+		// must not be enough to move it
+		match, err := uc.passwordHasher.Verify(user.PasswordHash, update.CurrentPassword)
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			return nil, domain.ErrIncorrectPassword
+		}
+		user.Email = entities.NormalizeEmail(*update.Email)
+		user.EmailVerifiedAt = nil
+	}
+
+	// Apply remaining fields
+	if update.FirstName != nil {
+		user.FirstName = *update.FirstName
+	}
+	if update.LastName != nil {
+		user.LastName = *update.LastName
+	}
+	if update.DateOfBirth != nil {
+		if err := uc.checkDateOfBirth(*update.DateOfBirth); err != nil {
+			return nil, err
+		}
+		user.DateOfBirth = *update.DateOfBirth
+	}
+
+	if err := uc.userRepository.Update(user); err != nil {
+		return nil, err
+	}
+
+	if usernameChanged {
+		uc.recordEvent(entities.AuthEventUsernameChanged, user.ID, client, map[string]string{
+			"previous_username": previousUsername,
+			"username":          user.Username,
+		})
+	}
+	if emailChanged {
+		uc.recordEvent(entities.AuthEventEmailChanged, user.ID, client, map[string]string{
+			"previous_email": previousEmail,
+			"email":          user.Email,
+		})
+	}
+
+	// Ask the user to confirm the new address and let the previous one know, so that the
+	// owner notices a change they did not make; they can request another email if this fails
+	if emailChanged {
+		if err := uc.sendVerificationEmail(user); err != nil {
+			log.Printf("Failed to send verification email to user %s: %v", user.ID, err)
+		}
+		if err := uc.sendEmailChangedNotice(user, previousEmail); err != nil {
+			log.Printf("Failed to send email change notice to user %s: %v", user.ID, err)
+		}
+	}
+
+	return user, nil
+}
+
+// sendEmailChangedNotice tells the previous email address of a user about the change
+func (uc *AuthUseCase) sendEmailChangedNotice(user *entities.User, previousEmail string) error {
+	return uc.mailer.Send(EmailMessage{
+		To:      previousEmail,
+		Subject: "Your Musicfy email address was changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe email address of your Musicfy account was changed to %s. If you did not make this change, please contact support right away.\n",
+			user.FirstName, user.Email,
+		),
+	})
+}
@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"errors"
+	"musicfy/internal/auth/domain"
+	"testing"
+)
+
+func TestUpdateProfileEmailRequiresCurrentPassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+	}{
+		{name: "missing password", password: ""},
+		{name: "wrong password", password: "Wrong-password-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tc, user := newAuthTestUseCase(t)
+			email := "mallory@example.com"
+
+			_, err := tc.UpdateProfile(user.ID, ProfileUpdate{Email: &email, CurrentPassword: tt.password}, ClientInfo{})
+			if !errors.Is(err, domain.ErrIncorrectPassword) {
+				t.Fatalf("got %v, want %v", err, domain.ErrIncorrectPassword)
+			}
+
+			stored, _ := tc.users.FindByID(user.ID)
+			if stored.Email != user.Email {
+				t.Errorf("email changed to %q", stored.Email)
+			}
+			if len(tc.mailer.sent) != 0 {
+				t.Errorf("sent %d emails, want none", len(tc.mailer.sent))
+			}
+		})
+	}
+}
+
+func TestUpdateProfileEmailNotifiesPreviousAddress(t *testing.T) {
+	tc, user := newAuthTestUseCase(t)
+	email := "Ada.New@Example.com"
+
+	updated, err := tc.UpdateProfile(user.ID, ProfileUpdate{Email: &email, CurrentPassword: "Old-password-1"}, ClientInfo{})
+	if err != nil {
+		t.Fatalf("UpdateProfile: %v", err)
+	}
+	if updated.Email != "ada.new@example.com" || updated.EmailVerifiedAt != nil {
+		t.Errorf("email = %q, verified at %v; want the normalised address, unverified", updated.Email, updated.EmailVerifiedAt)
+	}
+
+	recipients := map[string]bool{}
+	for _, message := range tc.mailer.sent {
+		recipients[message.To] = true
+	}
+	if len(tc.mailer.sent) != 2 || !recipients[user.Email] || !recipients[updated.Email] {
+		t.Errorf("emails sent to %v, want a notice to %s and a verification email to %s", recipients, user.Email, updated.Email)
+	}
+}
+
+func TestUpdateProfileWithoutEmailChangeNeedsNoPassword(t *testing.T) {
+	tc, user := newAuthTestUseCase(t)
+	firstName := "Augusta"
+	sameEmail := user.Email
+
+	updated, err := tc.UpdateProfile(user.ID, ProfileUpdate{FirstName: &firstName, Email: &sameEmail}, ClientInfo{})
+	if err != nil {
+		t.Fatalf("UpdateProfile: %v", err)
+	}
+	if updated.FirstName != firstName || len(tc.mailer.sent) != 0 {
+		t.Errorf("first name = %q with %d emails sent", updated.FirstName, len(tc.mailer.sent))
+	}
+}
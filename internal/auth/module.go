@@ -6,7 +6,8 @@ import (
 	"github.com/gorilla/mux"
 )
 
-// RegisterRoutes registers all auth routes with the given router
-func RegisterRoutes(router *mux.Router) {
-	routes.RegisterAuthRoutes(router)
+// RegisterRoutes registers all auth routes with the given API router. Well-known
+// endpoints such as the JWKS are registered on the root router.
+func RegisterRoutes(rootRouter, apiRouter *mux.Router) {
+	routes.RegisterAuthRoutes(rootRouter, apiRouter)
 }
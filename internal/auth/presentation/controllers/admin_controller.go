@@ -0,0 +1,418 @@
+package controllers
+
+import (
+	"errors"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"musicfy/internal/auth/domain/usecases"
+	"musicfy/internal/auth/presentation/dtos"
+	"musicfy/internal/shared"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// AdminController handles account administration HTTP requests
+type AdminController struct {
+	adminUseCase *usecases.AdminUseCase
+}
+
+// NewAdminController creates a new admin controller
+func NewAdminController(adminUseCase *usecases.AdminUseCase) *AdminController {
+	return &AdminController{
+		adminUseCase: adminUseCase,
+	}
+}
+
+// ListUsers lists users with pagination, search and filters
+func (c *AdminController) ListUsers(w http.ResponseWriter, r *http.Request) {
+	// Parse filter from query parameters
+	filter, err := parseUserFilter(r)
+	if err != nil {
+		shared.Error(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
+		return
+	}
+
+	// Get users through use case
+	users, total, err := c.adminUseCase.ListUsers(filter)
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Map users to response DTOs
+	response := dtos.UserListResponse{
+		Users: make([]dtos.AdminUserResponse, len(users)),
+		Total: total,
+	}
+	for i, user := range users {
+		response.Users[i] = c.mapUserToAdminResponse(user)
+	}
+
+	// Return success response with users
+	shared.Success(w, "Users retrieved successfully", response)
+}
+
+// GetUser returns a user with their account state
+func (c *AdminController) GetUser(w http.ResponseWriter, r *http.Request) {
+	// Parse user ID from path
+	userID, err := getUserIDFromPath(r)
+	if err != nil {
+		shared.Error(w, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Get user through use case
+	details, err := c.adminUseCase.GetUser(userID)
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response with user details
+	shared.Success(w, "User retrieved successfully", dtos.AdminUserDetailsResponse{
+		AdminUserResponse: c.mapUserToAdminResponse(details.User),
+		TwoFactorEnabled:  details.TwoFactorEnabled,
+		ActiveSessions:    details.ActiveSessions,
+		LockedUntil:       details.LockedUntil,
+	})
+}
+
+// SuspendUser blocks a user from signing in
+func (c *AdminController) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	// Get admin ID from context (set by auth middleware)
+	adminID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse user ID from path
+	userID, err := getUserIDFromPath(r)
+	if err != nil {
+		shared.Error(w, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Parse and validate the optional request body
+	var req dtos.SuspendUserRequest
+	if err := decodeAndValidateOptionalRequest(w, r, &req); err != nil {
+		return
+	}
+
+	// Suspend user through use case
+	if err := c.adminUseCase.SuspendUser(adminID, userID, req.Reason, clientInfoFromRequest(r)); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response
+	shared.Success(w, "User suspended successfully", nil)
+}
+
+// UnsuspendUser lets a suspended user sign in again
+func (c *AdminController) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	// Get admin ID from context (set by auth middleware)
+	adminID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse user ID from path
+	userID, err := getUserIDFromPath(r)
+	if err != nil {
+		shared.Error(w, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Unsuspend user through use case
+	if err := c.adminUseCase.UnsuspendUser(adminID, userID, clientInfoFromRequest(r)); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response
+	shared.Success(w, "User unsuspended successfully", nil)
+}
+
+// ForcePasswordReset signs a user out and makes them choose a new password
+func (c *AdminController) ForcePasswordReset(w http.ResponseWriter, r *http.Request) {
+	// Get admin ID from context (set by auth middleware)
+	adminID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse user ID from path
+	userID, err := getUserIDFromPath(r)
+	if err != nil {
+		shared.Error(w, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Force reset through use case
+	if err := c.adminUseCase.ForcePasswordReset(adminID, userID, clientInfoFromRequest(r)); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response
+	shared.Success(w, "Password reset forced successfully", nil)
+}
+
+// DeleteUser permanently removes a user
+func (c *AdminController) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	// Get admin ID from context (set by auth middleware)
+	adminID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse user ID from path
+	userID, err := getUserIDFromPath(r)
+	if err != nil {
+		shared.Error(w, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Delete user through use case
+	if err := c.adminUseCase.DeleteUser(adminID, userID, clientInfoFromRequest(r)); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response
+	shared.Success(w, "User deleted successfully", nil)
+}
+
+// UnlockUser lifts a login lockout on a user account
+func (c *AdminController) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	// Get admin ID from context (set by auth middleware)
+	adminID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse user ID from path
+	userID, err := getUserIDFromPath(r)
+	if err != nil {
+		shared.Error(w, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	// Unlock account through use case
+	if err := c.adminUseCase.UnlockUser(adminID, userID, clientInfoFromRequest(r)); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response
+	shared.Success(w, "User unlocked successfully", nil)
+}
+
+// ListAuthEvents queries the authentication audit log, optionally for one user
+func (c *AdminController) ListAuthEvents(w http.ResponseWriter, r *http.Request) {
+	// Parse filter from query parameters
+	filter, err := parseAuthEventFilter(r)
+	if err != nil {
+		shared.Error(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
+		return
+	}
+	if value := r.URL.Query().Get("user_id"); value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			shared.Error(w, http.StatusBadRequest, "Invalid user ID", err.Error())
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	// Query events through use case
+	events, err := c.adminUseCase.ListAuthEvents(filter)
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response with events
+	shared.Success(w, "Auth events retrieved successfully", mapAuthEventsToResponse(events))
+}
+
+// CreateInvite mints an invite code
+func (c *AdminController) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	// Get admin ID from context (set by auth middleware)
+	adminID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse and validate request body
+	var req dtos.CreateInviteRequest
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays != nil {
+		t := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	// Create invite through use case
+	invite, code, err := c.adminUseCase.CreateInvite(adminID, req.Note, req.MaxUses, expiresAt, clientInfoFromRequest(r))
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return the code, which cannot be retrieved again
+	shared.JSON(w, http.StatusCreated, shared.BaseResponse{
+		IsSucess: true,
+		Message:  "Invite created; copy the code now, it will not be shown again",
+		Data: dtos.CreatedInviteResponse{
+			InviteResponse: mapInviteToResponse(invite),
+			Code:           code,
+		},
+	})
+}
+
+// ListInvites lists invites with pagination, newest first
+func (c *AdminController) ListInvites(w http.ResponseWriter, r *http.Request) {
+	// Parse pagination from query parameters
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		shared.Error(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
+		return
+	}
+
+	// Get invites through use case
+	invites, err := c.adminUseCase.ListInvites(limit, offset)
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Map invites to response DTOs
+	response := make([]dtos.InviteResponse, len(invites))
+	for i, invite := range invites {
+		response[i] = mapInviteToResponse(invite)
+	}
+
+	// Return success response with invites
+	shared.Success(w, "Invites retrieved successfully", response)
+}
+
+// GetInvite returns an invite with the users who registered with it
+func (c *AdminController) GetInvite(w http.ResponseWriter, r *http.Request) {
+	// Parse invite ID from path
+	inviteID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		shared.Error(w, http.StatusBadRequest, "Invalid invite ID", err.Error())
+		return
+	}
+
+	// Get invite through use case
+	details, err := c.adminUseCase.GetInvite(inviteID)
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Map redemptions to response DTOs
+	response := dtos.InviteDetailsResponse{
+		InviteResponse: mapInviteToResponse(details.Invite),
+		Redemptions:    make([]dtos.InviteRedemptionResponse, len(details.Redemptions)),
+	}
+	for i, redemption := range details.Redemptions {
+		response.Redemptions[i] = dtos.InviteRedemptionResponse{
+			UserID:     redemption.UserID.String(),
+			RedeemedAt: redemption.RedeemedAt,
+		}
+	}
+
+	// Return success response with invite details
+	shared.Success(w, "Invite retrieved successfully", response)
+}
+
+// parseUserFilter reads the search, status, role, email_verified, limit and offset query
+// parameters of a user listing
+func parseUserFilter(r *http.Request) (repositories.UserFilter, error) {
+	query := r.URL.Query()
+	filter := repositories.UserFilter{
+		Search: strings.TrimSpace(query.Get("search")),
+		Status: entities.UserStatus(query.Get("status")),
+		Role:   entities.Role(query.Get("role")),
+	}
+
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return filter, errors.New("unknown status")
+	}
+	if value := query.Get("email_verified"); value != "" {
+		verified, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, errors.New("email_verified must be true or false")
+		}
+		filter.EmailVerified = &verified
+	}
+
+	var err error
+	filter.Limit, filter.Offset, err = parsePagination(r)
+	return filter, err
+}
+
+// parsePagination reads the limit and offset query parameters; zero means unset
+func parsePagination(r *http.Request) (limit, offset int, err error) {
+	query := r.URL.Query()
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			return 0, 0, errors.New("limit must be a positive integer")
+		}
+	}
+	if value := query.Get("offset"); value != "" {
+		if offset, err = strconv.Atoi(value); err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+	}
+	return limit, offset, nil
+}
+
+// mapUserToAdminResponse maps a user entity to an admin response DTO
+func (c *AdminController) mapUserToAdminResponse(user *entities.User) dtos.AdminUserResponse {
+	return dtos.AdminUserResponse{
+		ID:                  user.ID.String(),
+		UserProfileResponse: mapUserToProfileResponse(user, c.adminUseCase.AvatarURLs(user)),
+		Status:              string(user.Status),
+	}
+}
+
+// mapInviteToResponse maps an invite entity to a response DTO
+func mapInviteToResponse(invite *entities.Invite) dtos.InviteResponse {
+	response := dtos.InviteResponse{
+		ID:         invite.ID.String(),
+		CodePrefix: invite.CodePrefix,
+		Note:       invite.Note,
+		MaxUses:    invite.MaxUses,
+		UseCount:   invite.UseCount,
+		ExpiresAt:  invite.ExpiresAt,
+		CreatedAt:  invite.CreatedAt,
+	}
+	if invite.CreatedBy != nil {
+		createdBy := invite.CreatedBy.String()
+		response.CreatedBy = &createdBy
+	}
+	return response
+}
+
+// getUserIDFromPath parses the {id} path variable
+func getUserIDFromPath(r *http.Request) (uuid.UUID, error) {
+	return uuid.Parse(mux.Vars(r)["id"])
+}
@@ -1,31 +1,35 @@
 package controllers
 
 import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"math/big"
 	"musicfy/internal/auth/domain"
 	"musicfy/internal/auth/domain/entities"
 	"musicfy/internal/auth/domain/usecases"
 	"musicfy/internal/auth/presentation/dtos"
+	"musicfy/internal/auth/presentation/middleware"
 	"musicfy/internal/shared"
 	"net/http"
 	"strconv"
+	"time"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"github.com/gorilla/mux"
 )
 
 // AuthController handles authentication-related HTTP requests
 type AuthController struct {
 	authUseCase *usecases.AuthUseCase
-	validate    *validator.Validate
 }
 
 // NewAuthController creates a new auth controller
 func NewAuthController(authUseCase *usecases.AuthUseCase) *AuthController {
 	return &AuthController{
 		authUseCase: authUseCase,
-		validate:    validator.New(),
 	}
 }
 
@@ -33,14 +37,14 @@ func NewAuthController(authUseCase *usecases.AuthUseCase) *AuthController {
 func (c *AuthController) Register(w http.ResponseWriter, r *http.Request) {
 	// Parse and validate request body
 	var req dtos.RegisterRequest
-	if err := c.decodeAndValidateRequest(w, r, &req); err != nil {
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
 		return
 	}
 
-	// Convert age string to int
-	age, err := strconv.Atoi(req.Age)
+	// Parse date of birth; the format was checked by validation
+	dateOfBirth, err := time.Parse(entities.DateOfBirthLayout, req.DateOfBirth)
 	if err != nil {
-		shared.Error(w, http.StatusBadRequest, "Invalid age format", err.Error())
+		shared.Error(w, http.StatusBadRequest, "Invalid date of birth format", err.Error())
 		return
 	}
 
@@ -51,9 +55,11 @@ func (c *AuthController) Register(w http.ResponseWriter, r *http.Request) {
 		req.Username,
 		req.Email,
 		req.Password,
-		age,
+		dateOfBirth,
+		req.InviteCode,
+		clientInfoFromRequest(r),
 	); err != nil {
-		c.handleUseCaseError(w, err)
+		handleUseCaseError(w, err)
 		return
 	}
 
@@ -66,28 +72,121 @@ func (c *AuthController) Register(w http.ResponseWriter, r *http.Request) {
 func (c *AuthController) Login(w http.ResponseWriter, r *http.Request) {
 	// Parse and validate request body
 	var req dtos.LoginRequest
-	if err := c.decodeAndValidateRequest(w, r, &req); err != nil {
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
 		return
 	}
 
 	// Authenticate user through use case
-	token, err := c.authUseCase.LoginUser(req.UsernameOrEmail, req.Password)
+	client := clientInfoFromRequest(r)
+	client.DeviceName = req.DeviceName
+	result, err := c.authUseCase.LoginUser(req.UsernameOrEmail, req.Password, client)
 	if err != nil {
-		c.handleUseCaseError(w, err)
+		handleUseCaseError(w, err)
 		return
 	}
 
-	// Return success response with token
-	w.WriteHeader(http.StatusOK)
-	shared.Success(w, "Login successful", dtos.LoginResponse{
-		Token: token,
+	// Return tokens or a second factor challenge
+	c.writeLoginResult(w, result, req.UseCookies)
+}
+
+// LoginTwoFactor completes a login with a TOTP or recovery code
+func (c *AuthController) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	// Parse and validate request body
+	var req dtos.TwoFactorLoginRequest
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	// Complete login through use case
+	client := clientInfoFromRequest(r)
+	client.DeviceName = req.DeviceName
+	tokens, err := c.authUseCase.CompleteTwoFactorLogin(req.ChallengeToken, req.Code, client)
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response with tokens
+	c.writeTokens(w, "Login successful", tokens, req.UseCookies)
+}
+
+// RequestMagicLink emails a passwordless login link
+func (c *AuthController) RequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	// Parse and validate request body
+	var req dtos.MagicLinkRequest
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	// Send link through use case
+	binding, err := c.authUseCase.RequestMagicLink(req.Email, clientInfoFromRequest(r))
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return the device binding, which the client keeps until the link is opened
+	shared.Success(w, "If an account with that email exists, a login link has been sent", dtos.MagicLinkResponse{
+		DeviceBinding: binding,
 	})
 }
 
+// ConsumeMagicLink completes a passwordless login
+func (c *AuthController) ConsumeMagicLink(w http.ResponseWriter, r *http.Request) {
+	// Parse and validate request body
+	var req dtos.ConsumeMagicLinkRequest
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	// Authenticate user through use case
+	client := clientInfoFromRequest(r)
+	client.DeviceName = req.DeviceName
+	result, err := c.authUseCase.CompleteMagicLinkLogin(req.Token, req.DeviceBinding, client)
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return tokens or a second factor challenge
+	c.writeLoginResult(w, result, req.UseCookies)
+}
+
+// Refresh exchanges a refresh token for a new token pair
+func (c *AuthController) Refresh(w http.ResponseWriter, r *http.Request) {
+	// Parse and validate request body; cookie-mode clients may send none
+	var req dtos.RefreshTokenRequest
+	if err := decodeAndValidateOptionalRequest(w, r, &req); err != nil {
+		return
+	}
+
+	// Fall back to the cookie of a cookie-mode login, which is refreshed in the same mode
+	refreshToken, useCookies := req.RefreshToken, false
+	if refreshToken == "" {
+		if cookie, err := r.Cookie(middleware.RefreshTokenCookie); err == nil && cookie.Value != "" {
+			refreshToken, useCookies = cookie.Value, true
+		}
+	}
+	if refreshToken == "" {
+		shared.Error(w, http.StatusBadRequest, "Validation failed", "refresh_token is required")
+		return
+	}
+
+	// Rotate refresh token through use case
+	tokens, err := c.authUseCase.RefreshTokens(refreshToken, clientInfoFromRequest(r))
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response with tokens
+	c.writeTokens(w, "Token refreshed successfully", tokens, useCookies)
+}
+
 // GetProfile retrieves the profile of the authenticated user
 func (c *AuthController) GetProfile(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
-	userID, err := c.getUserIDFromContext(r)
+	userID, err := getUserIDFromContext(r)
 	if err != nil {
 		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
 		return
@@ -96,80 +195,672 @@ func (c *AuthController) GetProfile(w http.ResponseWriter, r *http.Request) {
 	// Get user from use case
 	user, err := c.authUseCase.GetUserByID(userID)
 	if err != nil {
-		c.handleUseCaseError(w, err)
+		handleUseCaseError(w, err)
 		return
 	}
 
 	// Map user entity to response DTO
-	response := c.mapUserToProfileResponse(user)
+	response := mapUserToProfileResponse(user, c.authUseCase.AvatarURLs(user))
 
 	// Return success response
 	w.WriteHeader(http.StatusOK)
 	shared.Success(w, "User retrieved successfully", response)
 }
 
-// Helper functions
+// UpdateProfile applies a partial update to the profile of the authenticated user
+func (c *AuthController) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse and validate request body
+	var req dtos.UpdateProfileRequest
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	update := usecases.ProfileUpdate{
+		FirstName:       req.FirstName,
+		LastName:        req.LastName,
+		Username:        req.Username,
+		Email:           req.Email,
+		CurrentPassword: req.CurrentPassword,
+	}
 
-// decodeAndValidateRequest decodes and validates the request body
-func (c *AuthController) decodeAndValidateRequest(w http.ResponseWriter, r *http.Request, req interface{}) error {
-	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
-		shared.Error(w, http.StatusBadRequest, "Invalid JSON body", err.Error())
-		return err
+	// Parse date of birth
+	if req.DateOfBirth != nil {
+		dateOfBirth, err := time.Parse(entities.DateOfBirthLayout, *req.DateOfBirth)
+		if err != nil {
+			shared.Error(w, http.StatusBadRequest, "Invalid date of birth format", err.Error())
+			return
+		}
+		update.DateOfBirth = &dateOfBirth
 	}
 
-	if err := c.validate.Struct(req); err != nil {
-		shared.Error(w, http.StatusBadRequest, "Validation failed", err.Error())
-		return err
+	// Update profile through use case
+	user, err := c.authUseCase.UpdateProfile(userID, update, clientInfoFromRequest(r))
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
 	}
 
-	return nil
+	// Return success response with the updated profile
+	shared.Success(w, "Profile updated successfully", mapUserToProfileResponse(user, c.authUseCase.AvatarURLs(user)))
 }
 
-// getUserIDFromContext extracts and validates the user ID from the request context
-func (c *AuthController) getUserIDFromContext(r *http.Request) (uuid.UUID, error) {
-	userID := r.Context().Value("userID")
-	if userID == nil {
-		return uuid.Nil, errors.New("user not found in context")
+// UpdateAvatar replaces the avatar of the authenticated user with an image uploaded as the
+// "avatar" field of a multipart form
+func (c *AuthController) UpdateAvatar(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
 	}
 
-	userIDStr, ok := userID.(string)
-	if !ok {
-		return uuid.Nil, errors.New("user ID in context is not a string")
+	// Read the uploaded file; the use case rejects content over the size limit
+	content, err := readAvatarUpload(w, r)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			handleUseCaseError(w, domain.ErrAvatarTooLarge)
+			return
+		}
+		shared.Error(w, http.StatusBadRequest, "Invalid avatar upload", err.Error())
+		return
 	}
 
-	uuidValue, err := uuid.Parse(userIDStr)
+	// Update avatar through use case
+	user, err := c.authUseCase.UpdateAvatar(userID, content)
 	if err != nil {
-		return uuid.Nil, errors.New("invalid user ID format")
+		handleUseCaseError(w, err)
+		return
 	}
 
-	return uuidValue, nil
+	// Return success response with the updated profile
+	shared.Success(w, "Avatar updated successfully", mapUserToProfileResponse(user, c.authUseCase.AvatarURLs(user)))
 }
 
-// mapUserToProfileResponse maps a user entity to a profile response DTO
-func (c *AuthController) mapUserToProfileResponse(user *entities.User) dtos.UserProfileResponse {
-	return dtos.UserProfileResponse{
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Email:     user.Email,
-		Username:  user.Username,
-		Age:       user.Age,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-	}
-}
-
-// handleUseCaseError maps use case errors to appropriate HTTP responses
-func (c *AuthController) handleUseCaseError(w http.ResponseWriter, err error) {
-	switch {
-	case errors.Is(err, domain.ErrUserNotFound):
-		shared.Error(w, http.StatusNotFound, "User not found", err.Error())
-	case errors.Is(err, domain.ErrUsernameExists), errors.Is(err, domain.ErrEmailExists):
-		shared.Error(w, http.StatusConflict, err.Error(), nil)
-	case errors.Is(err, domain.ErrInvalidPassword):
-		shared.Error(w, http.StatusUnauthorized, "Invalid credentials", nil)
-	case errors.Is(err, domain.ErrJWTGeneration):
-		shared.Error(w, http.StatusInternalServerError, "Authentication error", nil)
+// Logout revokes the current access token and its session
+func (c *AuthController) Logout(w http.ResponseWriter, r *http.Request) {
+	// Get principal from context (set by auth middleware)
+	principal, err := getPrincipalFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Revoke tokens through use case
+	claims := &usecases.JWTClaims{
+		TokenID:   principal.TokenID,
+		UserID:    principal.UserID,
+		SessionID: principal.SessionID,
+		ExpiresAt: principal.ExpiresAt,
+	}
+	if err := c.authUseCase.Logout(claims, clientInfoFromRequest(r)); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response, removing the cookies of a cookie-mode login
+	clearSessionCookies(w)
+	shared.Success(w, "Logged out successfully", nil)
+}
+
+// LogoutAll revokes every token issued to the authenticated user
+func (c *AuthController) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Revoke tokens through use case
+	if err := c.authUseCase.LogoutAll(userID, clientInfoFromRequest(r)); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response, removing the cookies of a cookie-mode login
+	clearSessionCookies(w)
+	shared.Success(w, "Logged out from all devices successfully", nil)
+}
+
+// VerifyEmail confirms an email address using the token sent by email.
+// The token is read from the "token" query parameter on GET and from the body on POST.
+func (c *AuthController) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	// Read token from query string or request body
+	var req dtos.VerifyEmailRequest
+	if r.Method == http.MethodGet {
+		req.Token = r.URL.Query().Get("token")
+		if err := validate.Struct(req); err != nil {
+			shared.Error(w, http.StatusBadRequest, "Validation failed", err.Error())
+			return
+		}
+	} else if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	// Verify email through use case
+	if err := c.authUseCase.VerifyEmail(req.Token); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response
+	shared.Success(w, "Email verified successfully", nil)
+}
+
+// ResendVerification sends a new verification email
+func (c *AuthController) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	// Parse and validate request body
+	var req dtos.ResendVerificationRequest
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	// Resend email through use case
+	if err := c.authUseCase.ResendVerificationEmail(req.Email); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return the same response whether or not the account exists
+	shared.Success(w, "If the account exists and is not verified, a verification email has been sent", nil)
+}
+
+// ForgotPassword emails a password reset link
+func (c *AuthController) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	// Parse and validate request body
+	var req dtos.ForgotPasswordRequest
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	// Request reset through use case
+	if err := c.authUseCase.ForgotPassword(req.Email); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return the same response whether or not the account exists
+	shared.Success(w, "If an account with that email exists, a password reset link has been sent", nil)
+}
+
+// ResetPassword sets a new password using a reset token
+func (c *AuthController) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	// Parse and validate request body
+	var req dtos.ResetPasswordRequest
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	// Reset password through use case
+	if err := c.authUseCase.ResetPassword(req.Token, req.Password, clientInfoFromRequest(r)); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response
+	shared.Success(w, "Password reset successfully", nil)
+}
+
+// ChangePassword changes the password of the authenticated user
+func (c *AuthController) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse and validate request body
+	var req dtos.ChangePasswordRequest
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	// Change password through use case
+	tokens, err := c.authUseCase.ChangePassword(userID, req.CurrentPassword, req.NewPassword, req.RevokeOtherSessions, clientInfoFromRequest(r))
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response, with replacement tokens if other sessions were revoked
+	if tokens != nil {
+		c.writeTokens(w, "Password changed successfully", tokens, middleware.UsesSessionCookies(r))
+		return
+	}
+	shared.Success(w, "Password changed successfully", nil)
+}
+
+// SetupTwoFactor starts a TOTP enrollment for the authenticated user
+func (c *AuthController) SetupTwoFactor(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Start enrollment through use case
+	setup, err := c.authUseCase.SetupTwoFactor(userID)
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response with the secret
+	shared.Success(w, "Scan the provisioning URI and confirm with a code", dtos.TwoFactorSetupResponse{
+		Secret:          setup.Secret,
+		ProvisioningURI: setup.ProvisioningURI,
+	})
+}
+
+// ConfirmTwoFactor enables two-factor authentication with the first TOTP code
+func (c *AuthController) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse and validate request body
+	var req dtos.TwoFactorCodeRequest
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	// Confirm enrollment through use case
+	codes, err := c.authUseCase.ConfirmTwoFactor(userID, req.Code, clientInfoFromRequest(r))
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response with recovery codes
+	shared.Success(w, "Two-factor authentication enabled", dtos.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// DisableTwoFactor turns off two-factor authentication for the authenticated user
+func (c *AuthController) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse and validate request body
+	var req dtos.DisableTwoFactorRequest
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	// Disable through use case
+	if err := c.authUseCase.DisableTwoFactor(userID, req.Password, req.Code, clientInfoFromRequest(r)); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response
+	shared.Success(w, "Two-factor authentication disabled", nil)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the authenticated user
+func (c *AuthController) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse and validate request body
+	var req dtos.TwoFactorCodeRequest
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	// Regenerate codes through use case
+	codes, err := c.authUseCase.RegenerateRecoveryCodes(userID, req.Code, clientInfoFromRequest(r))
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response with recovery codes
+	shared.Success(w, "Recovery codes regenerated", dtos.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// ListSessions lists the devices the authenticated user is signed in on
+func (c *AuthController) ListSessions(w http.ResponseWriter, r *http.Request) {
+	// Get principal from context (set by auth middleware)
+	principal, err := getPrincipalFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Get sessions through use case
+	sessions, err := c.authUseCase.ListSessions(principal.UserID)
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Map sessions to response DTOs
+	response := make([]dtos.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		response[i] = dtos.SessionResponse{
+			ID:         session.ID.String(),
+			DeviceName: session.DeviceName,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			Current:    session.ID == principal.SessionID,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+		}
+	}
+
+	// Return success response with sessions
+	shared.Success(w, "Sessions retrieved successfully", response)
+}
+
+// RevokeSession signs the authenticated user out of one of their sessions
+func (c *AuthController) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse session ID from path
+	sessionID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		shared.Error(w, http.StatusBadRequest, "Invalid session ID", err.Error())
+		return
+	}
+
+	// Revoke session through use case
+	if err := c.authUseCase.RevokeSession(userID, sessionID, clientInfoFromRequest(r)); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response
+	shared.Success(w, "Session revoked successfully", nil)
+}
+
+// CreatePersonalAccessToken mints a scoped token for scripts and integrations
+func (c *AuthController) CreatePersonalAccessToken(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse and validate request body
+	var req dtos.CreatePersonalAccessTokenRequest
+	if err := decodeAndValidateRequest(w, r, &req); err != nil {
+		return
+	}
+
+	scopes := make([]entities.Permission, len(req.Scopes))
+	for i, scope := range req.Scopes {
+		scopes[i] = entities.Permission(scope)
+	}
+	var expiresAt *time.Time
+	if req.ExpiresInDays != nil {
+		t := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	// Create token through use case
+	token, value, err := c.authUseCase.CreatePersonalAccessToken(userID, req.Name, scopes, expiresAt, clientInfoFromRequest(r))
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return the token value, which cannot be retrieved again
+	shared.JSON(w, http.StatusCreated, shared.BaseResponse{
+		IsSucess: true,
+		Message:  "Personal access token created; copy it now, it will not be shown again",
+		Data: dtos.CreatedPersonalAccessTokenResponse{
+			PersonalAccessTokenResponse: c.mapPersonalAccessTokenToResponse(token),
+			Token:                       value,
+		},
+	})
+}
+
+// ListPersonalAccessTokens lists the authenticated user's personal access tokens
+func (c *AuthController) ListPersonalAccessTokens(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Get tokens through use case
+	tokens, err := c.authUseCase.ListPersonalAccessTokens(userID)
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Map tokens to response DTOs
+	response := make([]dtos.PersonalAccessTokenResponse, len(tokens))
+	for i, token := range tokens {
+		response[i] = c.mapPersonalAccessTokenToResponse(token)
+	}
+
+	// Return success response with tokens
+	shared.Success(w, "Personal access tokens retrieved successfully", response)
+}
+
+// RevokePersonalAccessToken revokes one of the authenticated user's personal access tokens
+func (c *AuthController) RevokePersonalAccessToken(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse token ID from path
+	tokenID, err := uuid.Parse(mux.Vars(r)["id"])
+	if err != nil {
+		shared.Error(w, http.StatusBadRequest, "Invalid token ID", err.Error())
+		return
+	}
+
+	// Revoke token through use case
+	if err := c.authUseCase.RevokePersonalAccessToken(userID, tokenID, clientInfoFromRequest(r)); err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response
+	shared.Success(w, "Personal access token revoked successfully", nil)
+}
+
+// ListAuthEvents lists the authenticated user's authentication history
+func (c *AuthController) ListAuthEvents(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from context (set by auth middleware)
+	userID, err := getUserIDFromContext(r)
+	if err != nil {
+		shared.Error(w, http.StatusInternalServerError, err.Error(), nil)
+		return
+	}
+
+	// Parse filter from query parameters
+	filter, err := parseAuthEventFilter(r)
+	if err != nil {
+		shared.Error(w, http.StatusBadRequest, "Invalid query parameters", err.Error())
+		return
+	}
+
+	// Get events through use case
+	events, err := c.authUseCase.ListAuthEvents(userID, filter)
+	if err != nil {
+		handleUseCaseError(w, err)
+		return
+	}
+
+	// Return success response with events
+	shared.Success(w, "Auth events retrieved successfully", mapAuthEventsToResponse(events))
+}
+
+// JWKS publishes the token verification keys as a JSON Web Key Set
+func (c *AuthController) JWKS(w http.ResponseWriter, r *http.Request) {
+	publicKeys := c.authUseCase.PublicKeys()
+
+	keys := make([]dtos.JWKResponse, 0, len(publicKeys))
+	for _, key := range publicKeys {
+		if jwk, ok := c.mapPublicKeyToJWK(key); ok {
+			keys = append(keys, jwk)
+		}
+	}
+
+	// The standard format is returned as is, without the response envelope
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dtos.JWKSResponse{Keys: keys})
+}
+
+// Helper functions
+
+// mapPublicKeyToJWK maps an RSA or Ed25519 verification key to a JSON Web Key
+func (c *AuthController) mapPublicKeyToJWK(key usecases.PublicKey) (dtos.JWKResponse, bool) {
+	jwk := dtos.JWKResponse{
+		KeyID:     key.KeyID,
+		Use:       "sig",
+		Algorithm: key.Algorithm,
+	}
+
+	switch k := key.Key.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.Modulus = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+		jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(k)
 	default:
+		return jwk, false
+	}
+
+	return jwk, true
+}
+
+// mapUserToProfileResponse maps a user entity and the URLs of their avatar by size to a
+// profile response DTO
+func mapUserToProfileResponse(user *entities.User, avatarURLs map[int]string) dtos.UserProfileResponse {
+	roles := make([]string, len(user.Roles))
+	for i, role := range user.Roles {
+		roles[i] = string(role)
+	}
+
+	var avatars map[string]string
+	if avatarURLs != nil {
+		avatars = make(map[string]string, len(avatarURLs))
+		for size, url := range avatarURLs {
+			avatars[strconv.Itoa(size)] = url
+		}
+	}
+
+	return dtos.UserProfileResponse{
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Email:         user.Email,
+		Username:      user.Username,
+		DateOfBirth:   user.DateOfBirth.Format(entities.DateOfBirthLayout),
+		Age:           user.Age(),
+		IsMinor:       user.IsMinor(),
+		AvatarURLs:    avatars,
+		Roles:         roles,
+		EmailVerified: user.IsEmailVerified(),
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
+
+// writeLoginResult writes the tokens of a completed login, or the challenge of a login
+// that requires a second factor
+func (c *AuthController) writeLoginResult(w http.ResponseWriter, result *usecases.LoginResult, useCookies bool) {
+	// Ask for a second factor when enabled
+	if result.Tokens == nil {
+		shared.Success(w, "Two-factor authentication required", dtos.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    result.ChallengeToken,
+			ExpiresIn:         int(result.ChallengeExpiresIn.Seconds()),
+		})
+		return
+	}
+
+	// Return success response with tokens
+	c.writeTokens(w, "Login successful", result.Tokens, useCookies)
+}
+
+// writeTokens writes issued tokens in the response body or, in cookie mode, as HttpOnly cookies
+// with only the CSRF token in the body
+func (c *AuthController) writeTokens(w http.ResponseWriter, message string, tokens *usecases.AuthTokens, useCookies bool) {
+	if !useCookies {
+		shared.Success(w, message, c.mapTokensToLoginResponse(tokens))
+		return
+	}
+
+	csrfToken, err := setSessionCookies(w, tokens)
+	if err != nil {
 		shared.Error(w, http.StatusInternalServerError, "Internal server error", err.Error())
+		return
+	}
+	shared.Success(w, message, dtos.CookieLoginResponse{
+		CSRFToken: csrfToken,
+		ExpiresIn: int(tokens.ExpiresIn.Seconds()),
+	})
+}
+
+// mapTokensToLoginResponse maps issued tokens to a login response DTO
+func (c *AuthController) mapTokensToLoginResponse(tokens *usecases.AuthTokens) dtos.LoginResponse {
+	return dtos.LoginResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(tokens.ExpiresIn.Seconds()),
+	}
+}
+
+// mapPersonalAccessTokenToResponse maps a personal access token entity to a response DTO
+func (c *AuthController) mapPersonalAccessTokenToResponse(token *entities.PersonalAccessToken) dtos.PersonalAccessTokenResponse {
+	scopes := make([]string, len(token.Scopes))
+	for i, scope := range token.Scopes {
+		scopes[i] = string(scope)
+	}
+
+	return dtos.PersonalAccessTokenResponse{
+		ID:         token.ID.String(),
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+		CreatedAt:  token.CreatedAt,
 	}
 }
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"musicfy/internal/auth/domain/usecases"
+	"musicfy/internal/auth/presentation/middleware"
+	"musicfy/internal/config"
+	"net/http"
+)
+
+// refreshCookiePath limits the refresh token cookie to the refresh endpoint
+const refreshCookiePath = "/api/v1/auth/refresh"
+
+// setSessionCookies stores the tokens of a cookie-mode login in HttpOnly cookies, next to a
+// new CSRF token that scripts of the page can read. It returns the CSRF token.
+func setSessionCookies(w http.ResponseWriter, tokens *usecases.AuthTokens) (string, error) {
+	csrfToken, err := newCSRFToken()
+	if err != nil {
+		return "", err
+	}
+
+	refreshMaxAge := config.AppConfig.JWTConfig.RefreshExpiryHours * 3600
+	http.SetCookie(w, sessionCookie(middleware.AccessTokenCookie, tokens.AccessToken, "/", int(tokens.ExpiresIn.Seconds()), true))
+	http.SetCookie(w, sessionCookie(middleware.RefreshTokenCookie, tokens.RefreshToken, refreshCookiePath, refreshMaxAge, true))
+	http.SetCookie(w, sessionCookie(middleware.CSRFCookie, csrfToken, "/", refreshMaxAge, false))
+	return csrfToken, nil
+}
+
+// clearSessionCookies removes the cookies of a cookie-mode login
+func clearSessionCookies(w http.ResponseWriter) {
+	http.SetCookie(w, sessionCookie(middleware.AccessTokenCookie, "", "/", -1, true))
+	http.SetCookie(w, sessionCookie(middleware.RefreshTokenCookie, "", refreshCookiePath, -1, true))
+	http.SetCookie(w, sessionCookie(middleware.CSRFCookie, "", "/", -1, false))
+}
+
+// sessionCookie builds a session cookie with the configured attributes
+func sessionCookie(name, value, path string, maxAge int, httpOnly bool) *http.Cookie {
+	cfg := config.AppConfig.CookieConfig
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   cfg.Domain,
+		MaxAge:   maxAge,
+		Secure:   cfg.Secure,
+		HttpOnly: httpOnly,
+		SameSite: sameSiteMode(cfg.SameSite),
+	}
+}
+
+// sameSiteMode converts a configured SameSite value; anything unknown is strict
+func sameSiteMode(value string) http.SameSite {
+	switch value {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteStrictMode
+	}
+}
+
+// newCSRFToken generates a random double-submit CSRF token
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"musicfy/internal/auth/domain"
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/domain/repositories"
+	"musicfy/internal/auth/domain/usecases"
+	"musicfy/internal/auth/presentation/dtos"
+	"musicfy/internal/config"
+	"musicfy/internal/shared"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// validate is the validator shared by all controllers
+var validate = validator.New()
+
+// decodeAndValidateRequest decodes and validates the request body
+func decodeAndValidateRequest(w http.ResponseWriter, r *http.Request, req interface{}) error {
+	return decodeRequest(w, r, req, false)
+}
+
+// decodeAndValidateOptionalRequest is decodeAndValidateRequest for endpoints whose body may be
+// left out entirely; an empty body validates as the zero value of req
+func decodeAndValidateOptionalRequest(w http.ResponseWriter, r *http.Request, req interface{}) error {
+	return decodeRequest(w, r, req, true)
+}
+
+// decodeRequest decodes and validates a JSON request body, writing a 400 response on failure
+func decodeRequest(w http.ResponseWriter, r *http.Request, req interface{}, optional bool) error {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil && !(optional && errors.Is(err, io.EOF)) {
+		shared.Error(w, http.StatusBadRequest, "Invalid JSON body", err.Error())
+		return err
+	}
+
+	if err := validate.Struct(req); err != nil {
+		shared.Error(w, http.StatusBadRequest, "Validation failed", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// getUserIDFromContext returns the ID of the authenticated user from the request context
+func getUserIDFromContext(r *http.Request) (uuid.UUID, error) {
+	principal, err := getPrincipalFromContext(r)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return principal.UserID, nil
+}
+
+// getPrincipalFromContext returns the request principal set by the auth middleware
+func getPrincipalFromContext(r *http.Request) (*shared.Principal, error) {
+	principal, ok := shared.PrincipalFrom(r.Context())
+	if !ok {
+		return nil, errors.New("principal not found in context")
+	}
+	return principal, nil
+}
+
+// clientInfoFromRequest describes the client that sent the request
+func clientInfoFromRequest(r *http.Request) usecases.ClientInfo {
+	return usecases.ClientInfo{
+		IPAddress: shared.ClientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+// avatarFormOverhead is the room left for multipart headers and boundaries on top of the
+// largest accepted avatar
+const avatarFormOverhead = 64 << 10
+
+// readAvatarUpload reads the "avatar" file of a multipart form. Bodies far over the avatar
+// size limit fail with an *http.MaxBytesError; a file just over it is returned for the use
+// case to reject.
+func readAvatarUpload(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	maxBytes := int64(config.AppConfig.AuthConfig.AvatarMaxBytes)
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+avatarFormOverhead)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("the avatar file is missing")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "avatar" {
+			return io.ReadAll(io.LimitReader(part, maxBytes+1))
+		}
+	}
+}
+
+// parseAuthEventFilter reads the type, from, to, limit and offset query parameters of an
+// audit log listing; times are RFC 3339
+func parseAuthEventFilter(r *http.Request) (repositories.AuthEventFilter, error) {
+	query := r.URL.Query()
+	filter := repositories.AuthEventFilter{Type: entities.AuthEventType(query.Get("type"))}
+
+	var err error
+	if value := query.Get("from"); value != "" {
+		if filter.From, err = time.Parse(time.RFC3339, value); err != nil {
+			return filter, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if value := query.Get("to"); value != "" {
+		if filter.To, err = time.Parse(time.RFC3339, value); err != nil {
+			return filter, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		if filter.Limit, err = strconv.Atoi(value); err != nil || filter.Limit < 1 {
+			return filter, errors.New("limit must be a positive integer")
+		}
+	}
+	if value := query.Get("offset"); value != "" {
+		if filter.Offset, err = strconv.Atoi(value); err != nil || filter.Offset < 0 {
+			return filter, errors.New("offset must be a non-negative integer")
+		}
+	}
+
+	return filter, nil
+}
+
+// mapAuthEventsToResponse maps audit log entries to response DTOs
+func mapAuthEventsToResponse(events []*entities.AuthEvent) []dtos.AuthEventResponse {
+	response := make([]dtos.AuthEventResponse, len(events))
+	for i, event := range events {
+		response[i] = dtos.AuthEventResponse{
+			ID:        event.ID.String(),
+			Type:      string(event.Type),
+			IPAddress: event.IPAddress,
+			UserAgent: event.UserAgent,
+			Metadata:  event.Metadata,
+			CreatedAt: event.CreatedAt,
+		}
+		if event.UserID != nil {
+			userID := event.UserID.String()
+			response[i].UserID = &userID
+		}
+	}
+	return response
+}
+
+// handleUseCaseError maps use case errors to appropriate HTTP responses
+func handleUseCaseError(w http.ResponseWriter, err error) {
+	// Password policy failures carry the list of broken rules
+	var policyErr *domain.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		violations := make([]dtos.PasswordViolationResponse, len(policyErr.Violations))
+		for i, violation := range policyErr.Violations {
+			violations[i] = dtos.PasswordViolationResponse{Code: violation.Code, Message: violation.Message}
+		}
+		shared.Error(w, http.StatusBadRequest, "Password does not meet the password policy", violations)
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		shared.Error(w, http.StatusNotFound, "User not found", err.Error())
+	case errors.Is(err, domain.ErrUsernameExists), errors.Is(err, domain.ErrEmailExists):
+		shared.Error(w, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, domain.ErrInvalidPassword):
+		shared.Error(w, http.StatusUnauthorized, "Invalid credentials", nil)
+	case errors.Is(err, domain.ErrInvalidRefreshToken), errors.Is(err, domain.ErrRefreshTokenReused):
+		shared.Error(w, http.StatusUnauthorized, err.Error(), nil)
+	case errors.Is(err, domain.ErrIncorrectPassword):
+		shared.Error(w, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, domain.ErrPasswordUnchanged):
+		shared.Error(w, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, domain.ErrEmailNotVerified):
+		shared.Error(w, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, domain.ErrInvalidVerification), errors.Is(err, domain.ErrInvalidResetToken):
+		shared.Error(w, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, domain.ErrTooManyRequests):
+		shared.Error(w, http.StatusTooManyRequests, err.Error(), nil)
+	case errors.Is(err, domain.ErrAccountLocked):
+		shared.Error(w, http.StatusLocked, err.Error(), nil)
+	case errors.Is(err, domain.ErrTooManyLoginAttempts):
+		shared.Error(w, http.StatusTooManyRequests, err.Error(), nil)
+	case errors.Is(err, domain.ErrTwoFactorAlreadyEnabled), errors.Is(err, domain.ErrTwoFactorNotEnabled):
+		shared.Error(w, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, domain.ErrInvalidTwoFactorCode), errors.Is(err, domain.ErrInvalidChallenge),
+		errors.Is(err, domain.ErrInvalidMagicLink):
+		shared.Error(w, http.StatusUnauthorized, err.Error(), nil)
+	case errors.Is(err, domain.ErrSessionNotFound):
+		shared.Error(w, http.StatusNotFound, "Session not found", err.Error())
+	case errors.Is(err, domain.ErrPersonalAccessTokenNotFound):
+		shared.Error(w, http.StatusNotFound, "Personal access token not found", err.Error())
+	case errors.Is(err, domain.ErrInvalidScope), errors.Is(err, domain.ErrInvalidExpiry):
+		shared.Error(w, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, domain.ErrAccountSuspended), errors.Is(err, domain.ErrPasswordResetRequired):
+		shared.Error(w, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, domain.ErrUserSuspended), errors.Is(err, domain.ErrUserNotSuspended):
+		shared.Error(w, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, domain.ErrCannotManageOwnAccount):
+		shared.Error(w, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, domain.ErrRegistrationClosed), errors.Is(err, domain.ErrInviteRequired):
+		shared.Error(w, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, domain.ErrInvalidInvite):
+		shared.Error(w, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, domain.ErrInviteNotFound):
+		shared.Error(w, http.StatusNotFound, "Invite not found", err.Error())
+	case errors.Is(err, domain.ErrInvalidDateOfBirth), errors.Is(err, domain.ErrInvalidUsername):
+		shared.Error(w, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, domain.ErrBelowMinimumAge):
+		shared.Error(w, http.StatusForbidden, err.Error(), nil)
+	case errors.Is(err, domain.ErrAvatarTooLarge):
+		shared.Error(w, http.StatusRequestEntityTooLarge, err.Error(), nil)
+	case errors.Is(err, domain.ErrUnsupportedImage):
+		shared.Error(w, http.StatusUnsupportedMediaType, err.Error(), nil)
+	case errors.Is(err, domain.ErrInvalidImageDimensions):
+		shared.Error(w, http.StatusBadRequest, err.Error(), nil)
+	case errors.Is(err, domain.ErrAvatarConflict):
+		shared.Error(w, http.StatusConflict, err.Error(), nil)
+	case errors.Is(err, domain.ErrJWTGeneration):
+		shared.Error(w, http.StatusInternalServerError, "Authentication error", nil)
+	default:
+		shared.Error(w, http.StatusInternalServerError, "Internal server error", err.Error())
+	}
+}
@@ -4,14 +4,114 @@ package dtos
 type LoginRequest struct {
 	UsernameOrEmail string `json:"username_or_email" validate:"required"`
 	Password        string `json:"password" validate:"required"`
+	DeviceName      string `json:"device_name" validate:"max=100"`
+	UseCookies      bool   `json:"use_cookies"`
 }
 
 // RegisterRequest represents the registration request data
 type RegisterRequest struct {
-	FirstName string `json:"first_name" validate:"required,min=2"`
-	LastName  string `json:"last_name" validate:"required,min=2"`
-	Username  string `json:"username" validate:"required,min=3"`
-	Password  string `json:"password" validate:"required,min=8,max=20"`
-	Email     string `json:"email" validate:"required,email"`
-	Age       string `json:"age" validate:"required"`
+	FirstName   string `json:"first_name" validate:"required,min=2,max=100"`
+	LastName    string `json:"last_name" validate:"required,min=2,max=100"`
+	Username    string `json:"username" validate:"required,min=3,max=100,excludes=@"`
+	Password    string `json:"password" validate:"required"`
+	Email       string `json:"email" validate:"required,max=100,email"`
+	DateOfBirth string `json:"date_of_birth" validate:"required,datetime=2006-01-02"`
+	InviteCode  string `json:"invite_code" validate:"max=64"`
+}
+
+// RefreshTokenRequest represents the token refresh request data; the refresh token is read
+// from its cookie when omitted
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// VerifyEmailRequest represents the email verification request data
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ResendVerificationRequest represents the verification email resend request data
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ForgotPasswordRequest represents the forgot password request data
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest represents the password reset request data
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ChangePasswordRequest represents the change password request data
+type ChangePasswordRequest struct {
+	CurrentPassword     string `json:"current_password" validate:"required"`
+	NewPassword         string `json:"new_password" validate:"required"`
+	RevokeOtherSessions bool   `json:"revoke_other_sessions"`
+}
+
+// UpdateProfileRequest represents the profile update request data; omitted fields are left unchanged
+type UpdateProfileRequest struct {
+	FirstName   *string `json:"first_name" validate:"omitempty,min=2,max=100"`
+	LastName    *string `json:"last_name" validate:"omitempty,min=2,max=100"`
+	Username    *string `json:"username" validate:"omitempty,min=3,max=100,excludes=@"`
+	Email       *string `json:"email" validate:"omitempty,max=100,email"`
+	DateOfBirth *string `json:"date_of_birth" validate:"omitempty,datetime=2006-01-02"`
+
+	// CurrentPassword is required to change the email address
+	CurrentPassword string `json:"current_password" validate:"required_with=Email"`
+}
+
+// TwoFactorLoginRequest represents the second step of a login with two-factor authentication
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+	DeviceName     string `json:"device_name" validate:"max=100"`
+	UseCookies     bool   `json:"use_cookies"`
+}
+
+// TwoFactorCodeRequest represents a request confirmed with a TOTP code
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// DisableTwoFactorRequest represents the two-factor authentication disable request data
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// CreatePersonalAccessTokenRequest represents the personal access token creation request data
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,required"`
+	ExpiresInDays *int     `json:"expires_in_days" validate:"omitempty,min=1,max=3650"`
+}
+
+// SuspendUserRequest represents the optional body of an admin suspension request
+type SuspendUserRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+// MagicLinkRequest represents the passwordless login link request data
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ConsumeMagicLinkRequest represents the passwordless login completion request data
+type ConsumeMagicLinkRequest struct {
+	Token         string `json:"token" validate:"required"`
+	DeviceBinding string `json:"device_binding" validate:"required"`
+	DeviceName    string `json:"device_name" validate:"max=100"`
+	UseCookies    bool   `json:"use_cookies"`
+}
+
+// CreateInviteRequest represents the invite creation request data
+type CreateInviteRequest struct {
+	Note          string `json:"note" validate:"max=255"`
+	MaxUses       int    `json:"max_uses" validate:"required,min=1,max=10000"`
+	ExpiresInDays *int   `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
 }
@@ -0,0 +1,81 @@
+package dtos
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestRegisterRequestLengthLimits(t *testing.T) {
+	valid := func() RegisterRequest {
+		return RegisterRequest{
+			FirstName:   "John",
+			LastName:    "Doe",
+			Username:    "johndoe",
+			Password:    "secret",
+			Email:       "john@example.com",
+			DateOfBirth: "1999-04-23",
+		}
+	}
+	long := strings.Repeat("a", 101)
+
+	tests := []struct {
+		name    string
+		modify  func(req *RegisterRequest)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(req *RegisterRequest) {}},
+		{name: "first name at the limit", modify: func(req *RegisterRequest) { req.FirstName = long[:100] }},
+		{name: "first name over the limit", modify: func(req *RegisterRequest) { req.FirstName = long }, wantErr: true},
+		{name: "last name over the limit", modify: func(req *RegisterRequest) { req.LastName = long }, wantErr: true},
+		{name: "username over the limit", modify: func(req *RegisterRequest) { req.Username = long }, wantErr: true},
+		{name: "multi-byte username at the limit", modify: func(req *RegisterRequest) { req.Username = strings.Repeat("é", 100) }},
+		{name: "email at the limit", modify: func(req *RegisterRequest) { req.Email = long[:88] + "@example.com" }},
+		{name: "email over the limit", modify: func(req *RegisterRequest) { req.Email = long[:89] + "@example.com" }, wantErr: true},
+	}
+
+	validate := validator.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.modify(&req)
+			if err := validate.Struct(req); (err != nil) != tt.wantErr {
+				t.Errorf("Struct() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateProfileRequestLengthLimits(t *testing.T) {
+	long := strings.Repeat("a", 101)
+	email := long[:89] + "@example.com"
+
+	tests := []struct {
+		name    string
+		req     UpdateProfileRequest
+		wantErr bool
+	}{
+		{name: "empty", req: UpdateProfileRequest{}},
+		{name: "first name over the limit", req: UpdateProfileRequest{FirstName: &long}, wantErr: true},
+		{name: "last name over the limit", req: UpdateProfileRequest{LastName: &long}, wantErr: true},
+		{name: "username over the limit", req: UpdateProfileRequest{Username: &long}, wantErr: true},
+		{name: "email over the limit", req: UpdateProfileRequest{Email: &email, CurrentPassword: "secret"}, wantErr: true},
+	}
+
+	validate := validator.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validate.Struct(tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("Struct() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateProfileRequestEmailNeedsCurrentPassword(t *testing.T) {
+	email := "john@example.com"
+	if err := validator.New().Struct(UpdateProfileRequest{Email: &email}); err == nil {
+		t.Error("email change without current_password passed validation")
+	}
+}
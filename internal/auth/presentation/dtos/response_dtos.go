@@ -4,16 +4,166 @@ import "time"
 
 // UserProfileResponse represents the user profile data
 type UserProfileResponse struct {
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Age       int       `json:"age"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	FirstName     string            `json:"first_name"`
+	LastName      string            `json:"last_name"`
+	Username      string            `json:"username"`
+	Email         string            `json:"email"`
+	DateOfBirth   string            `json:"date_of_birth"`
+	Age           int               `json:"age"`
+	IsMinor       bool              `json:"is_minor"`
+	AvatarURLs    map[string]string `json:"avatar_urls"`
+	Roles         []string          `json:"roles"`
+	EmailVerified bool              `json:"email_verified"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
 }
 
-// LoginResponse represents the login response data
+// LoginResponse represents the login and token refresh response data
 type LoginResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// CookieLoginResponse replaces LoginResponse when the tokens are set as cookies
+type CookieLoginResponse struct {
+	CSRFToken string `json:"csrf_token"`
+	ExpiresIn int    `json:"expires_in"`
+}
+
+// TwoFactorChallengeResponse is returned by login when a second factor is required
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+	ExpiresIn         int    `json:"expires_in"`
+}
+
+// TwoFactorSetupResponse represents the data needed to enroll an authenticator app
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// RecoveryCodesResponse represents a freshly generated set of recovery codes
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// JWKSResponse is a JSON Web Key Set as defined by RFC 7517
+type JWKSResponse struct {
+	Keys []JWKResponse `json:"keys"`
+}
+
+// JWKResponse is a single public JSON Web Key
+type JWKResponse struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+// SessionResponse represents a device the user is signed in on
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// PersonalAccessTokenResponse represents a personal access token without its value
+type PersonalAccessTokenResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedPersonalAccessTokenResponse is returned once, when a personal access token is created
+type CreatedPersonalAccessTokenResponse struct {
+	PersonalAccessTokenResponse
 	Token string `json:"token"`
 }
+
+// PasswordViolationResponse describes one password policy rule a password breaks
+type PasswordViolationResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// AuthEventResponse represents an entry of the authentication audit log
+type AuthEventResponse struct {
+	ID        string            `json:"id"`
+	UserID    *string           `json:"user_id"`
+	Type      string            `json:"type"`
+	IPAddress string            `json:"ip_address"`
+	UserAgent string            `json:"user_agent"`
+	Metadata  map[string]string `json:"metadata"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// AdminUserResponse represents a user in admin listings
+type AdminUserResponse struct {
+	ID string `json:"id"`
+	UserProfileResponse
+	Status string `json:"status"`
+}
+
+// AdminUserDetailsResponse represents a user with their account state
+type AdminUserDetailsResponse struct {
+	AdminUserResponse
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	ActiveSessions   int        `json:"active_sessions"`
+	LockedUntil      *time.Time `json:"locked_until"`
+}
+
+// UserListResponse represents a page of users
+type UserListResponse struct {
+	Users []AdminUserResponse `json:"users"`
+	Total int                 `json:"total"`
+}
+
+// MagicLinkResponse is returned when a passwordless login link is requested
+type MagicLinkResponse struct {
+	DeviceBinding string `json:"device_binding"`
+}
+
+// InviteResponse represents an invite without its code
+type InviteResponse struct {
+	ID         string     `json:"id"`
+	CodePrefix string     `json:"code_prefix"`
+	Note       string     `json:"note"`
+	MaxUses    int        `json:"max_uses"`
+	UseCount   int        `json:"use_count"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedBy  *string    `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedInviteResponse is returned once, when an invite is created
+type CreatedInviteResponse struct {
+	InviteResponse
+	Code string `json:"code"`
+}
+
+// InviteRedemptionResponse represents the registration of a user with an invite
+type InviteRedemptionResponse struct {
+	UserID     string    `json:"user_id"`
+	RedeemedAt time.Time `json:"redeemed_at"`
+}
+
+// InviteDetailsResponse represents an invite with its redemptions
+type InviteDetailsResponse struct {
+	InviteResponse
+	Redemptions []InviteRedemptionResponse `json:"redemptions"`
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"musicfy/internal/shared"
+	"net/http"
+)
+
+// Cookies and header of the cookie session mode used by browsers
+const (
+	AccessTokenCookie  = "musicfy_access_token"
+	RefreshTokenCookie = "musicfy_refresh_token"
+	CSRFCookie         = "musicfy_csrf_token"
+	// CSRFHeader must repeat the value of the CSRF cookie on state-changing requests
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// UsesSessionCookies reports whether the request authenticates with session cookies rather
+// than an Authorization header
+func UsesSessionCookies(r *http.Request) bool {
+	if r.Header.Get("Authorization") != "" {
+		return false
+	}
+	for _, name := range []string{AccessTokenCookie, RefreshTokenCookie} {
+		if cookie, err := r.Cookie(name); err == nil && cookie.Value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// CSRFMiddleware protects cookie-authenticated requests with the double-submit pattern:
+// state-changing requests must copy the CSRF cookie into the X-CSRF-Token header, which
+// other sites can neither read nor set. Requests with an Authorization header are not affected.
+func CSRFMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isSafeMethod(r.Method) || !UsesSessionCookies(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cookie, err := r.Cookie(CSRFCookie)
+		header := r.Header.Get(CSRFHeader)
+		if err != nil || cookie.Value == "" || header == "" ||
+			subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(header)) != 1 {
+			shared.Error(w, http.StatusForbidden, "Forbidden: missing or invalid CSRF token", nil)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isSafeMethod reports whether requests with the method do not change state
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
@@ -1,7 +1,9 @@
 package middleware
 
 import (
-	"context"
+	"errors"
+	"fmt"
+	"musicfy/internal/auth/domain"
 	"musicfy/internal/auth/domain/usecases"
 	"musicfy/internal/shared"
 	"net/http"
@@ -10,37 +12,111 @@ import (
 
 // JWTMiddleware handles JWT authentication
 type JWTMiddleware struct {
-	jwtService usecases.JWTService
+	authUseCase *usecases.AuthUseCase
 }
 
 // NewJWTMiddleware creates a new JWT middleware
-func NewJWTMiddleware(jwtService usecases.JWTService) *JWTMiddleware {
+func NewJWTMiddleware(authUseCase *usecases.AuthUseCase) *JWTMiddleware {
 	return &JWTMiddleware{
-		jwtService: jwtService,
+		authUseCase: authUseCase,
 	}
 }
 
 // Middleware returns a middleware function that validates JWT tokens
 func (m *JWTMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Extract token from Authorization header
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+		// Extract token from Authorization header or session cookie
+		tokenString, ok := requestToken(r)
+		if !ok {
 			shared.Error(w, http.StatusUnauthorized, "Unauthorized: missing or invalid token", nil)
 			return
 		}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-
-		// Validate token
-		claims, err := m.jwtService.ValidateToken(tokenString)
+		// Validate token and check revocation
+		claims, err := m.authUseCase.AuthenticateToken(tokenString, usecases.ClientInfo{
+			IPAddress: shared.ClientIP(r),
+			UserAgent: r.UserAgent(),
+		})
 		if err != nil {
-			shared.Error(w, http.StatusUnauthorized, "Unauthorized: invalid token", nil)
+			if isTokenError(err) {
+				// RFC 6750 lets clients tell an expired token from a malformed one
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer error="invalid_token", error_description=%q`, err.Error()))
+				shared.Error(w, http.StatusUnauthorized, "Unauthorized: "+err.Error(), nil)
+				return
+			}
+			if errors.Is(err, domain.ErrAccountSuspended) || errors.Is(err, domain.ErrPasswordResetRequired) {
+				shared.Error(w, http.StatusForbidden, "Forbidden: "+err.Error(), nil)
+				return
+			}
+			shared.Error(w, http.StatusInternalServerError, "Internal server error", nil)
 			return
 		}
 
-		// Add user ID to context
-		ctx := context.WithValue(r.Context(), "userID", claims.UserID.String())
-		next.ServeHTTP(w, r.WithContext(ctx))
+		// Add the principal to the context for handlers of any module
+		next.ServeHTTP(w, r.WithContext(shared.WithPrincipal(r.Context(), principalFromClaims(claims))))
 	})
 }
+
+// requestToken returns the access token of the request. The Authorization header takes
+// precedence over the access token cookie set by cookie-mode logins.
+func requestToken(r *http.Request) (string, bool) {
+	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			return "", false
+		}
+		return strings.TrimPrefix(authHeader, "Bearer "), true
+	}
+
+	cookie, err := r.Cookie(AccessTokenCookie)
+	if err != nil || cookie.Value == "" {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+// principalFromClaims describes the authenticated token as a request principal
+func principalFromClaims(claims *usecases.JWTClaims) *shared.Principal {
+	principal := &shared.Principal{
+		UserID:    claims.UserID,
+		Username:  claims.Username,
+		Roles:     make([]string, len(claims.Roles)),
+		SessionID: claims.SessionID,
+		TokenID:   claims.TokenID,
+		ExpiresAt: claims.ExpiresAt,
+	}
+	for i, role := range claims.Roles {
+		principal.Roles[i] = string(role)
+	}
+	if claims.Scopes != nil {
+		principal.Scopes = make([]string, len(claims.Scopes))
+		for i, scope := range claims.Scopes {
+			principal.Scopes[i] = string(scope)
+		}
+	}
+	return principal
+}
+
+// tokenErrors are the reasons a presented token can be rejected
+var tokenErrors = []error{
+	domain.ErrInvalidToken,
+	domain.ErrTokenRevoked,
+	domain.ErrTokenMalformed,
+	domain.ErrTokenExpired,
+	domain.ErrTokenNotYetValid,
+	domain.ErrTokenSignatureInvalid,
+	domain.ErrTokenAlgorithm,
+	domain.ErrTokenInvalidIssuer,
+	domain.ErrTokenInvalidAudience,
+	domain.ErrTokenInvalidClaims,
+	domain.ErrSessionRevoked,
+}
+
+// isTokenError reports whether err rejects the token rather than being a server failure
+func isTokenError(err error) bool {
+	for _, tokenErr := range tokenErrors {
+		if errors.Is(err, tokenErr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/shared"
+	"net/http"
+)
+
+// RequireRole returns a middleware that only lets through users having at least one of the roles.
+// It must run after JWTMiddleware.Middleware.
+func RequireRole(roles ...entities.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := shared.PrincipalFrom(r.Context())
+			if !ok {
+				shared.Error(w, http.StatusUnauthorized, "Unauthorized: missing or invalid token", nil)
+				return
+			}
+
+			if !entities.HasAnyRole(principalRoles(principal), roles...) {
+				shared.Error(w, http.StatusForbidden, "Forbidden: insufficient role", nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequirePermission returns a middleware that only lets through users whose roles grant the permission.
+// Personal access tokens must also have the permission among their scopes.
+// It must run after JWTMiddleware.Middleware.
+func RequirePermission(permission entities.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := shared.PrincipalFrom(r.Context())
+			if !ok {
+				shared.Error(w, http.StatusUnauthorized, "Unauthorized: missing or invalid token", nil)
+				return
+			}
+
+			if !entities.RolesHavePermission(principalRoles(principal), permission) || !principal.HasScope(string(permission)) {
+				shared.Error(w, http.StatusForbidden, "Forbidden: missing permission "+string(permission), nil)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireSession returns a middleware that turns away personal access tokens, for account
+// endpoints that only a signed-in user may call. It must run after JWTMiddleware.Middleware.
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := shared.PrincipalFrom(r.Context())
+		if !ok {
+			shared.Error(w, http.StatusUnauthorized, "Unauthorized: missing or invalid token", nil)
+			return
+		}
+
+		if principal.IsPersonalAccessToken() {
+			shared.Error(w, http.StatusForbidden, "Forbidden: personal access tokens cannot be used here", nil)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// principalRoles returns the roles of a principal as domain roles
+func principalRoles(principal *shared.Principal) []entities.Role {
+	roles := make([]entities.Role, len(principal.Roles))
+	for i, role := range principal.Roles {
+		roles[i] = entities.Role(role)
+	}
+	return roles
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"musicfy/internal/auth/domain/entities"
+	"musicfy/internal/auth/presentation/controllers"
+	"musicfy/internal/auth/presentation/middleware"
+
+	"github.com/gorilla/mux"
+)
+
+// registerAdminRoutes sets up admin-only routes
+func registerAdminRoutes(router *mux.Router, adminController *controllers.AdminController, jwtMiddleware *middleware.JWTMiddleware) {
+	// Create subrouter for admin routes
+	adminRouter := router.PathPrefix("/admin").Subrouter()
+	// The permission check limits personal access tokens to those scoped for user management
+	adminRouter.Use(
+		jwtMiddleware.Middleware,
+		middleware.RequireRole(entities.RoleAdmin),
+		middleware.RequirePermission(entities.PermissionManageUsers),
+	)
+
+	// User management
+	adminRouter.HandleFunc("/users", adminController.ListUsers).Methods("GET")
+	adminRouter.HandleFunc("/users/{id}", adminController.GetUser).Methods("GET")
+	adminRouter.HandleFunc("/users/{id}", adminController.DeleteUser).Methods("DELETE")
+	adminRouter.HandleFunc("/users/{id}/suspend", adminController.SuspendUser).Methods("POST")
+	adminRouter.HandleFunc("/users/{id}/unsuspend", adminController.UnsuspendUser).Methods("POST")
+	adminRouter.HandleFunc("/users/{id}/force-password-reset", adminController.ForcePasswordReset).Methods("POST")
+	adminRouter.HandleFunc("/users/{id}/unlock", adminController.UnlockUser).Methods("POST")
+
+	// Invites
+	adminRouter.HandleFunc("/invites", adminController.CreateInvite).Methods("POST")
+	adminRouter.HandleFunc("/invites", adminController.ListInvites).Methods("GET")
+	adminRouter.HandleFunc("/invites/{id}", adminController.GetInvite).Methods("GET")
+
+	// Audit log
+	adminRouter.HandleFunc("/events", adminController.ListAuthEvents).Methods("GET")
+}
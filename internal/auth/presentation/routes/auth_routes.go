@@ -6,18 +6,47 @@ import (
 	"musicfy/internal/auth/domain/usecases"
 	"musicfy/internal/auth/presentation/controllers"
 	"musicfy/internal/auth/presentation/middleware"
+	"musicfy/internal/config"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 )
 
-// RegisterAuthRoutes sets up authentication routes
-func RegisterAuthRoutes(router *mux.Router) {
+// RegisterAuthRoutes sets up authentication routes under the API router and the
+// well-known endpoints under the root router
+func RegisterAuthRoutes(rootRouter, router *mux.Router) {
 	// Initialize dependencies
-	userRepository := repositories.NewUserRepository()
-	jwtService := services.NewJWTService()
-	authUseCase := usecases.NewAuthUseCase(userRepository, jwtService)
+	deps := usecases.AuthDependencies{
+		UserRepository:                repositories.NewUserRepository(),
+		RefreshTokenRepository:        repositories.NewRefreshTokenRepository(),
+		SessionRepository:             repositories.NewSessionRepository(),
+		PersonalAccessTokenRepository: repositories.NewPersonalAccessTokenRepository(),
+		RevokedTokenRepository:        repositories.NewRevokedTokenRepository(),
+		EmailVerificationRepository:   repositories.NewEmailVerificationRepository(),
+		PasswordResetRepository:       repositories.NewPasswordResetRepository(),
+		LoginThrottleRepository:       repositories.NewLoginThrottleRepository(),
+		TwoFactorRepository:           repositories.NewTwoFactorRepository(),
+		MagicLinkRepository:           repositories.NewMagicLinkRepository(),
+		InviteRepository:              repositories.NewInviteRepository(),
+		AuthEventRepository:           repositories.NewAuthEventRepository(),
+		JWTService:                    services.NewJWTService(),
+		PasswordHasher:                services.NewPasswordHasher(),
+		BreachedPasswordChecker:       services.NewBreachedPasswordChecker(),
+		TOTPService:                   services.NewTOTPService(),
+		Mailer:                        services.NewMailer(),
+		FileStorage:                   services.NewFileStorage(),
+		ImageProcessor:                services.NewImageProcessor(),
+	}
+	authUseCase := usecases.NewAuthUseCase(deps, newAuthSettings())
+	adminUseCase := usecases.NewAdminUseCase(deps, authUseCase)
 	authController := controllers.NewAuthController(authUseCase)
-	jwtMiddleware := middleware.NewJWTMiddleware(jwtService)
+	adminController := controllers.NewAdminController(adminUseCase)
+	jwtMiddleware := middleware.NewJWTMiddleware(authUseCase)
+
+	// Requests authenticated with session cookies must carry the CSRF token, in every module
+	router.Use(middleware.CSRFMiddleware)
 
 	// Create subrouter for auth routes
 	authRouter := router.PathPrefix("/auth").Subrouter()
@@ -25,9 +54,106 @@ func RegisterAuthRoutes(router *mux.Router) {
 	// Public routes
 	authRouter.HandleFunc("/register", authController.Register).Methods("POST")
 	authRouter.HandleFunc("/login", authController.Login).Methods("POST")
+	authRouter.HandleFunc("/login/2fa", authController.LoginTwoFactor).Methods("POST")
+	authRouter.HandleFunc("/magic-link", authController.RequestMagicLink).Methods("POST")
+	authRouter.HandleFunc("/magic-link/consume", authController.ConsumeMagicLink).Methods("POST")
+	authRouter.HandleFunc("/refresh", authController.Refresh).Methods("POST")
+	authRouter.HandleFunc("/verify-email", authController.VerifyEmail).Methods("GET", "POST")
+	authRouter.HandleFunc("/verify-email/resend", authController.ResendVerification).Methods("POST")
+	authRouter.HandleFunc("/password/forgot", authController.ForgotPassword).Methods("POST")
+	authRouter.HandleFunc("/password/reset", authController.ResetPassword).Methods("POST")
 
-	// Protected routes
+	// Protected routes; personal access tokens can only read the profile
 	protected := authRouter.PathPrefix("").Subrouter()
 	protected.Use(jwtMiddleware.Middleware)
 	protected.HandleFunc("/profile", authController.GetProfile).Methods("GET")
+
+	// Account routes, which require a signed-in session
+	account := protected.PathPrefix("").Subrouter()
+	account.Use(middleware.RequireSession)
+	account.HandleFunc("/profile", authController.UpdateProfile).Methods("PATCH")
+	account.HandleFunc("/profile/avatar", authController.UpdateAvatar).Methods("PUT")
+	account.HandleFunc("/password", authController.ChangePassword).Methods("PUT")
+	account.HandleFunc("/logout", authController.Logout).Methods("POST")
+	account.HandleFunc("/logout-all", authController.LogoutAll).Methods("POST")
+	account.HandleFunc("/sessions", authController.ListSessions).Methods("GET")
+	account.HandleFunc("/sessions/{id}", authController.RevokeSession).Methods("DELETE")
+	account.HandleFunc("/tokens", authController.CreatePersonalAccessToken).Methods("POST")
+	account.HandleFunc("/tokens", authController.ListPersonalAccessTokens).Methods("GET")
+	account.HandleFunc("/tokens/{id}", authController.RevokePersonalAccessToken).Methods("DELETE")
+	account.HandleFunc("/2fa/setup", authController.SetupTwoFactor).Methods("POST")
+	account.HandleFunc("/2fa/confirm", authController.ConfirmTwoFactor).Methods("POST")
+	account.HandleFunc("/2fa/disable", authController.DisableTwoFactor).Methods("POST")
+	account.HandleFunc("/2fa/recovery-codes", authController.RegenerateRecoveryCodes).Methods("POST")
+	account.HandleFunc("/events", authController.ListAuthEvents).Methods("GET")
+
+	// Admin routes
+	registerAdminRoutes(router, adminController, jwtMiddleware)
+
+	// Well-known routes
+	rootRouter.HandleFunc("/.well-known/jwks.json", authController.JWKS).Methods("GET")
+
+	// Files of the local storage driver; other drivers serve files themselves
+	if storage := config.AppConfig.StorageConfig; storage.Driver == "local" || storage.Driver == "" {
+		rootRouter.PathPrefix("/uploads/").Handler(http.StripPrefix("/uploads", uploadsHandler(storage.LocalDir))).Methods("GET", "HEAD")
+	}
+}
+
+// newAuthSettings builds the auth use case settings from the application configuration
+func newAuthSettings() usecases.AuthSettings {
+	cfg := config.AppConfig.AuthConfig
+	lockout := config.AppConfig.LockoutConfig
+	password := config.AppConfig.PasswordConfig
+
+	// bcrypt only reads a limited number of bytes
+	maxPasswordBytes := 0
+	if password.Algorithm == services.PasswordAlgorithmBcrypt {
+		maxPasswordBytes = services.BcryptMaxPasswordBytes
+	}
+
+	return usecases.AuthSettings{
+		PublicURL:                  config.AppConfig.ServerConfig.PublicURL,
+		RegistrationMode:           usecases.RegistrationMode(cfg.RegistrationMode),
+		MinimumAge:                 cfg.MinimumAge,
+		AvatarMaxBytes:             cfg.AvatarMaxBytes,
+		AvatarMaxDimension:         cfg.AvatarMaxDimension,
+		AvatarMaxPixels:            cfg.AvatarMaxPixels,
+		RequireEmailVerification:   cfg.RequireEmailVerification,
+		EmailVerificationExpiry:    time.Duration(cfg.EmailVerificationExpiryHours) * time.Hour,
+		VerificationResendInterval: time.Duration(cfg.VerificationResendIntervalSeconds) * time.Second,
+		VerificationMaxPerHour:     cfg.VerificationMaxPerHour,
+		PasswordResetURL:           cfg.PasswordResetURL,
+		PasswordResetExpiry:        time.Duration(cfg.PasswordResetExpiryMinutes) * time.Minute,
+		MagicLinkURL:               cfg.MagicLinkURL,
+		MagicLinkExpiry:            time.Duration(cfg.MagicLinkExpiryMinutes) * time.Minute,
+		MagicLinkMaxPerHour:        cfg.MagicLinkMaxPerHour,
+		MagicLinkIPMaxPerHour:      cfg.MagicLinkIPMaxPerHour,
+		Lockout: usecases.LockoutPolicy{
+			MaxAttempts:   lockout.MaxAttempts,
+			IPMaxAttempts: lockout.IPMaxAttempts,
+			BaseDuration:  time.Duration(lockout.BaseLockoutSeconds) * time.Second,
+			MaxDuration:   time.Duration(lockout.MaxLockoutSeconds) * time.Second,
+			Window:        time.Duration(lockout.AttemptWindowMinutes) * time.Minute,
+		},
+		PasswordPolicy: usecases.PasswordPolicy{
+			MinLength:               password.MinLength,
+			MaxLength:               password.MaxLength,
+			MaxBytes:                maxPasswordBytes,
+			MinCharacterClasses:     password.MinCharacterClasses,
+			RejectSimilarToIdentity: password.RejectSimilarToIdentity,
+		},
+	}
+}
+
+// uploadsHandler serves the files of a local storage directory, without directory listings
+func uploadsHandler(dir string) http.Handler {
+	fileServer := http.FileServer(http.Dir(dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		fileServer.ServeHTTP(w, r)
+	})
 }
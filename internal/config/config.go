@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/joho/godotenv"
@@ -23,10 +24,16 @@ const (
 
 // Config holds the application configuration
 type Config struct {
-	Environment  Environment
-	DBConfig     DatabaseConfig
-	ServerConfig ServerConfig
-	JWTConfig    JWTConfig
+	Environment    Environment
+	DBConfig       DatabaseConfig
+	ServerConfig   ServerConfig
+	JWTConfig      JWTConfig
+	AuthConfig     AuthConfig
+	LockoutConfig  LockoutConfig
+	PasswordConfig PasswordConfig
+	MailerConfig   MailerConfig
+	CookieConfig   CookieConfig
+	StorageConfig  StorageConfig
 }
 
 // DatabaseConfig holds database configuration
@@ -38,14 +45,94 @@ type DatabaseConfig struct {
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port string
-	Host string
+	Port              string
+	Host              string
+	PublicURL         string
+	TrustProxyHeaders bool
+	TrustedProxyHops  int // proxies in front of the server that append to X-Forwarded-For
 }
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret      string
-	ExpiryHours int
+	Secret              string
+	AccessExpiryMinutes int
+	RefreshExpiryHours  int
+	SigningKeysDir      string // directory of <kid>.pem RSA or Ed25519 keys; HS256 with Secret when empty
+	ActiveKeyID         string // kid used for signing; the greatest private kid when empty
+	KeyReloadSeconds    int
+	Issuer              string
+	Audience            string
+	ClockSkewSeconds    int
+}
+
+// AuthConfig holds account and authentication policy configuration
+type AuthConfig struct {
+	RegistrationMode                  string // open, invite_only or closed
+	MinimumAge                        int
+	AvatarMaxBytes                    int
+	AvatarMaxDimension                int
+	AvatarMaxPixels                   int
+	AvatarMaxConcurrentProcessing     int
+	RequireEmailVerification          bool
+	EmailVerificationExpiryHours      int
+	VerificationResendIntervalSeconds int
+	VerificationMaxPerHour            int
+	PasswordResetExpiryMinutes        int
+	PasswordResetURL                  string
+	MagicLinkExpiryMinutes            int
+	MagicLinkURL                      string
+	MagicLinkMaxPerHour               int
+	MagicLinkIPMaxPerHour             int
+	TOTPIssuer                        string
+}
+
+// LockoutConfig holds brute-force protection configuration for logins
+type LockoutConfig struct {
+	MaxAttempts          int
+	IPMaxAttempts        int
+	BaseLockoutSeconds   int
+	MaxLockoutSeconds    int
+	AttemptWindowMinutes int
+}
+
+// PasswordConfig holds password hashing and password policy configuration
+type PasswordConfig struct {
+	Algorithm               string // argon2id or bcrypt
+	BcryptCost              int
+	Argon2Memory            int // KiB
+	Argon2Iterations        int
+	Argon2Parallelism       int
+	Argon2MemoryBudget      int // KiB held by concurrent Argon2id computations
+	MinLength               int
+	MaxLength               int
+	MinCharacterClasses     int
+	RejectSimilarToIdentity bool
+	BreachedPasswordsDir    string // directory of <SHA-1 prefix>.txt range files; disabled when empty
+}
+
+// MailerConfig holds outgoing email configuration
+type MailerConfig struct {
+	Driver       string // smtp, file or log
+	From         string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	FilePath     string
+}
+
+// CookieConfig holds the attributes of the session cookies set by cookie-mode logins
+type CookieConfig struct {
+	Domain   string
+	Secure   bool
+	SameSite string // strict, lax or none
+}
+
+// StorageConfig holds the storage of uploaded files
+type StorageConfig struct {
+	Driver    string // local
+	LocalDir  string
+	PublicURL string // base URL of stored files
 }
 
 var (
@@ -70,15 +157,91 @@ func LoadConfig() {
 			IdleConns: getEnvAsInt("DB_IDLE_CONNS", 5),
 		},
 		ServerConfig: ServerConfig{
-			Port: getEnv("APP_PORT", "8080"),
-			Host: getEnv("APP_HOST", "0.0.0.0"),
+			Port:              getEnv("APP_PORT", "8080"),
+			Host:              getEnv("APP_HOST", "0.0.0.0"),
+			PublicURL:         strings.TrimSuffix(getEnv("APP_PUBLIC_URL", "http://localhost:8080"), "/"),
+			TrustProxyHeaders: getEnvAsBool("TRUST_PROXY_HEADERS", false),
+			TrustedProxyHops:  getEnvAsInt("TRUSTED_PROXY_HOPS", 1),
 		},
 		JWTConfig: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", "default_secret_change_in_production"),
-			ExpiryHours: getEnvAsInt("JWT_EXPIRY_HOURS", 24),
+			Secret:              getEnv("JWT_SECRET", "default_secret_change_in_production"),
+			AccessExpiryMinutes: getAccessExpiryMinutes(),
+			RefreshExpiryHours:  getEnvAsInt("JWT_REFRESH_EXPIRY_HOURS", 720),
+			SigningKeysDir:      getEnv("JWT_SIGNING_KEYS_DIR", ""),
+			ActiveKeyID:         getEnv("JWT_ACTIVE_KEY_ID", ""),
+			KeyReloadSeconds:    getEnvAsInt("JWT_KEY_RELOAD_SECONDS", 60),
+			Audience:            getEnv("JWT_AUDIENCE", "musicfy"),
+			ClockSkewSeconds:    getEnvAsInt("JWT_CLOCK_SKEW_SECONDS", 30),
+		},
+		AuthConfig: AuthConfig{
+			RegistrationMode:                  strings.ToLower(getEnv("REGISTRATION_MODE", "open")),
+			MinimumAge:                        getEnvAsInt("MINIMUM_AGE", 13),
+			AvatarMaxBytes:                    getEnvAsInt("AVATAR_MAX_BYTES", 5<<20),
+			AvatarMaxDimension:                getEnvAsInt("AVATAR_MAX_DIMENSION", 4096),
+			AvatarMaxPixels:                   getEnvAsInt("AVATAR_MAX_PIXELS", 4096*2048),
+			AvatarMaxConcurrentProcessing:     getEnvAsInt("AVATAR_MAX_CONCURRENT_PROCESSING", 2),
+			RequireEmailVerification:          getEnvAsBool("REQUIRE_EMAIL_VERIFICATION", false),
+			EmailVerificationExpiryHours:      getEnvAsInt("EMAIL_VERIFICATION_EXPIRY_HOURS", 24),
+			VerificationResendIntervalSeconds: getEnvAsInt("VERIFICATION_RESEND_INTERVAL_SECONDS", 60),
+			VerificationMaxPerHour:            getEnvAsInt("VERIFICATION_MAX_PER_HOUR", 5),
+			PasswordResetExpiryMinutes:        getEnvAsInt("PASSWORD_RESET_EXPIRY_MINUTES", 60),
+			MagicLinkExpiryMinutes:            getEnvAsInt("MAGIC_LINK_EXPIRY_MINUTES", 15),
+			MagicLinkMaxPerHour:               getEnvAsInt("MAGIC_LINK_MAX_PER_HOUR", 5),
+			MagicLinkIPMaxPerHour:             getEnvAsInt("MAGIC_LINK_IP_MAX_PER_HOUR", 20),
+			TOTPIssuer:                        getEnv("TOTP_ISSUER", "Musicfy"),
+		},
+		LockoutConfig: LockoutConfig{
+			MaxAttempts:          getEnvAsInt("LOGIN_MAX_ATTEMPTS", 5),
+			IPMaxAttempts:        getEnvAsInt("LOGIN_IP_MAX_ATTEMPTS", 50),
+			BaseLockoutSeconds:   getEnvAsInt("LOGIN_LOCKOUT_BASE_SECONDS", 60),
+			MaxLockoutSeconds:    getEnvAsInt("LOGIN_LOCKOUT_MAX_SECONDS", 3600),
+			AttemptWindowMinutes: getEnvAsInt("LOGIN_ATTEMPT_WINDOW_MINUTES", 15),
+		},
+		PasswordConfig: PasswordConfig{
+			Algorithm:               strings.ToLower(getEnv("PASSWORD_HASH_ALGORITHM", "argon2id")),
+			BcryptCost:              getEnvAsInt("BCRYPT_COST", 12),
+			Argon2Memory:            getEnvAsInt("ARGON2_MEMORY_KIB", 65536),
+			Argon2Iterations:        getEnvAsInt("ARGON2_ITERATIONS", 3),
+			Argon2Parallelism:       getEnvAsInt("ARGON2_PARALLELISM", 2),
+			Argon2MemoryBudget:      getEnvAsInt("ARGON2_MEMORY_BUDGET_KIB", 262144),
+			MinLength:               getEnvAsInt("PASSWORD_MIN_LENGTH", 8),
+			MaxLength:               getEnvAsInt("PASSWORD_MAX_LENGTH", 128),
+			MinCharacterClasses:     getEnvAsInt("PASSWORD_MIN_CHARACTER_CLASSES", 2),
+			RejectSimilarToIdentity: getEnvAsBool("PASSWORD_REJECT_SIMILAR_TO_IDENTITY", true),
+			BreachedPasswordsDir:    getEnv("BREACHED_PASSWORDS_DIR", ""),
+		},
+		MailerConfig: MailerConfig{
+			Driver:       strings.ToLower(getEnv("MAILER_DRIVER", "log")),
+			From:         getEnv("MAILER_FROM", "Musicfy <no-reply@musicfy.local>"),
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnvAsInt("SMTP_PORT", 587),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			FilePath:     getEnv("MAILER_FILE_PATH", "data/mail.log"),
+		},
+		CookieConfig: CookieConfig{
+			Domain:   getEnv("COOKIE_DOMAIN", ""),
+			Secure:   getEnvAsBool("COOKIE_SECURE", true),
+			SameSite: strings.ToLower(getEnv("COOKIE_SAME_SITE", "strict")),
+		},
+		StorageConfig: StorageConfig{
+			Driver:   strings.ToLower(getEnv("STORAGE_DRIVER", "local")),
+			LocalDir: getEnv("STORAGE_LOCAL_DIR", "data/uploads"),
 		},
 	}
 
+	// Tokens are issued by the public URL of this server unless configured otherwise
+	AppConfig.JWTConfig.Issuer = getEnv("JWT_ISSUER", AppConfig.ServerConfig.PublicURL)
+
+	// Reset links point to the page where users choose their new password
+	AppConfig.AuthConfig.PasswordResetURL = getEnv("PASSWORD_RESET_URL", AppConfig.ServerConfig.PublicURL+"/reset-password")
+
+	// Magic links point to the page that completes a passwordless login
+	AppConfig.AuthConfig.MagicLinkURL = getEnv("MAGIC_LINK_URL", AppConfig.ServerConfig.PublicURL+"/magic-login")
+
+	// The local storage driver serves files under /uploads unless they are served elsewhere
+	AppConfig.StorageConfig.PublicURL = strings.TrimSuffix(getEnv("STORAGE_PUBLIC_URL", AppConfig.ServerConfig.PublicURL+"/uploads"), "/")
+
 	// Log the current environment
 	log.Printf("Application running in %s mode", env)
 
@@ -112,10 +275,32 @@ func loadEnvFile() {
 
 // validateConfig validates critical configuration settings
 func validateConfig() {
-	// In production, ensure we have a proper JWT secret
-	if IsProduction() && (AppConfig.JWTConfig.Secret == "" ||
+	// In production, ensure we have signing keys or a proper JWT secret
+	if IsProduction() && AppConfig.JWTConfig.SigningKeysDir == "" && (AppConfig.JWTConfig.Secret == "" ||
 		AppConfig.JWTConfig.Secret == "default_secret_change_in_production") {
-		log.Fatalf("Production environment requires a secure JWT_SECRET to be set")
+		log.Fatalf("Production environment requires JWT_SIGNING_KEYS_DIR or a secure JWT_SECRET to be set")
+	}
+
+	// In production, emails must actually be delivered
+	if IsProduction() && AppConfig.MailerConfig.Driver != "smtp" {
+		log.Printf("Warning: MAILER_DRIVER is %q, emails will not be delivered", AppConfig.MailerConfig.Driver)
+	}
+
+	// An unknown registration mode would silently open or close sign-ups
+	switch AppConfig.AuthConfig.RegistrationMode {
+	case "open", "invite_only", "closed":
+	default:
+		log.Fatalf("REGISTRATION_MODE must be open, invite_only or closed, got %q", AppConfig.AuthConfig.RegistrationMode)
+	}
+
+	// Without a hop to count from, X-Forwarded-For cannot be read safely
+	if AppConfig.ServerConfig.TrustProxyHeaders && AppConfig.ServerConfig.TrustedProxyHops < 1 {
+		log.Fatalf("TRUSTED_PROXY_HOPS must be at least 1 when TRUST_PROXY_HEADERS is enabled")
+	}
+
+	// Browsers reject SameSite=None cookies without the Secure attribute
+	if AppConfig.CookieConfig.SameSite == "none" && !AppConfig.CookieConfig.Secure {
+		log.Fatalf("COOKIE_SAME_SITE=none requires COOKIE_SECURE to be enabled")
 	}
 
 	// Ensure database URL is set
@@ -148,6 +333,16 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
+// getAccessExpiryMinutes reads the access token lifetime. Deployments that still set the
+// deprecated JWT_EXPIRY_HOURS keep their lifetime until they switch to JWT_ACCESS_EXPIRY_MINUTES.
+func getAccessExpiryMinutes() int {
+	if legacyHours := getEnvAsInt("JWT_EXPIRY_HOURS", 0); legacyHours > 0 && os.Getenv("JWT_ACCESS_EXPIRY_MINUTES") == "" {
+		log.Printf("Warning: JWT_EXPIRY_HOURS is deprecated, set JWT_ACCESS_EXPIRY_MINUTES instead")
+		return legacyHours * 60
+	}
+	return getEnvAsInt("JWT_ACCESS_EXPIRY_MINUTES", 15)
+}
+
 func getEnvAsInt(key string, defaultValue int) int {
 	valueStr := os.Getenv(key)
 	if value, err := fmt.Sscanf(valueStr, "%d", &defaultValue); err != nil || value == 0 {
@@ -155,3 +350,11 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,19 @@
+-- Track when a user confirmed their email address
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Accounts created before email verification existed are considered verified
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+-- Create email verification tokens table
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create indexes for faster lookups
+CREATE INDEX IF NOT EXISTS idx_email_verification_tokens_user_id ON email_verification_tokens(user_id, created_at);
@@ -0,0 +1,10 @@
+-- Add roles to users; every account is a listener by default
+ALTER TABLE users ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT '{listener}';
+
+-- Only known roles may be assigned
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_roles_check;
+ALTER TABLE users ADD CONSTRAINT users_roles_check
+    CHECK (roles <@ ARRAY['listener', 'artist', 'curator', 'admin']::TEXT[]);
+
+-- Create index for role filtering
+CREATE INDEX IF NOT EXISTS idx_users_roles ON users USING GIN (roles);
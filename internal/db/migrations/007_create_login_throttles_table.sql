@@ -0,0 +1,7 @@
+-- Create login throttles table, keyed by account ("user:<id>") or client IP ("ip:<address>")
+CREATE TABLE IF NOT EXISTS login_throttles (
+    key VARCHAR(100) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP WITH TIME ZONE
+);
@@ -0,0 +1,24 @@
+-- Create sessions table; a session is one sign-in on a device and shares its ID with the
+-- refresh token family issued for it
+CREATE TABLE IF NOT EXISTS sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device_name VARCHAR(100) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create index for listing a user's sessions
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id);
+
+-- Keep existing sign-ins working by turning active refresh token families into sessions
+INSERT INTO sessions (id, user_id, device_name, created_at, last_seen_at, expires_at)
+SELECT family_id, user_id, 'Unknown device', MIN(created_at), MAX(created_at), MAX(expires_at)
+FROM refresh_tokens
+WHERE revoked_at IS NULL AND expires_at > NOW()
+GROUP BY family_id, user_id
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,14 @@
+-- Create authentication audit log; user_id has no foreign key so that history outlives the account
+CREATE TABLE IF NOT EXISTS auth_events (
+    id UUID PRIMARY KEY,
+    user_id UUID,
+    event_type VARCHAR(50) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for listing a user's events and querying by time range
+CREATE INDEX IF NOT EXISTS idx_auth_events_user_id_created_at ON auth_events(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_auth_events_created_at ON auth_events(created_at DESC);
@@ -0,0 +1,37 @@
+-- Refuse to migrate while accounts differ only by case or surrounding spaces; they have to be
+-- merged or renamed by hand first, and the error lists them
+DO $$
+DECLARE
+    conflicts TEXT;
+BEGIN
+    SELECT string_agg(kind || ' "' || value || '": ' || ids, '; ')
+    INTO conflicts
+    FROM (
+        SELECT 'username' AS kind, lower(btrim(username)) AS value, string_agg(id::TEXT, ', ' ORDER BY created_at) AS ids
+        FROM users
+        GROUP BY lower(btrim(username))
+        HAVING COUNT(*) > 1
+        UNION ALL
+        SELECT 'email', lower(btrim(email)), string_agg(id::TEXT, ', ' ORDER BY created_at)
+        FROM users
+        GROUP BY lower(btrim(email))
+        HAVING COUNT(*) > 1
+    ) duplicates;
+
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'case-insensitive username or email conflicts: %', conflicts;
+    END IF;
+END $$;
+
+-- Store identities in their normalized form
+UPDATE users SET username = btrim(username) WHERE username <> btrim(username);
+UPDATE users SET email = lower(btrim(email)) WHERE email <> lower(btrim(email));
+
+-- Replace case-sensitive uniqueness and lookup indexes with case-insensitive ones
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_username_key;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+DROP INDEX IF EXISTS idx_users_username;
+DROP INDEX IF EXISTS idx_users_email;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_lower_key ON users (lower(username));
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_key ON users (lower(email));
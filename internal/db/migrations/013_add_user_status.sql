@@ -0,0 +1,7 @@
+-- Add account status; suspended accounts and accounts awaiting a forced password reset cannot sign in
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status VARCHAR(30) NOT NULL DEFAULT 'active';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+ALTER TABLE users ADD CONSTRAINT users_status_check CHECK (status IN ('active', 'suspended', 'password_reset_required'));
+
+-- Create index for filtering users by status
+CREATE INDEX IF NOT EXISTS idx_users_status ON users(status);
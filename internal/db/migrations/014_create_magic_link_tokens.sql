@@ -0,0 +1,16 @@
+-- Create magic link tokens table; a link only works together with the device binding
+-- handed to the client that requested it
+CREATE TABLE IF NOT EXISTS magic_link_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    device_hash VARCHAR(64) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create indexes for rate limiting per account and per client IP
+CREATE INDEX IF NOT EXISTS idx_magic_link_tokens_user_id ON magic_link_tokens(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_magic_link_tokens_ip_address ON magic_link_tokens(ip_address, created_at);
@@ -0,0 +1,24 @@
+-- Create invites table; codes are stored hashed and shown by their prefix only
+CREATE TABLE IF NOT EXISTS invites (
+    id UUID PRIMARY KEY,
+    code_hash VARCHAR(64) NOT NULL UNIQUE,
+    code_prefix VARCHAR(16) NOT NULL,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    max_uses INTEGER NOT NULL CHECK (max_uses > 0),
+    use_count INTEGER NOT NULL DEFAULT 0 CHECK (use_count >= 0),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create invite redemptions table; a user registers with at most one invite
+CREATE TABLE IF NOT EXISTS invite_redemptions (
+    id UUID PRIMARY KEY,
+    invite_id UUID NOT NULL REFERENCES invites(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    redeemed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for listing invites and their redemptions
+CREATE INDEX IF NOT EXISTS idx_invites_created_at ON invites(created_at);
+CREATE INDEX IF NOT EXISTS idx_invite_redemptions_invite_id ON invite_redemptions(invite_id, redeemed_at);
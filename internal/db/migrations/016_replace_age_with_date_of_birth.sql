@@ -0,0 +1,9 @@
+-- Replace the age entered at registration with a date of birth, from which the age is computed.
+-- Existing users are estimated to be born half a year before they turned the age they gave
+-- when they registered, which is off by at most six months.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS date_of_birth DATE;
+UPDATE users
+SET date_of_birth = (created_at - make_interval(years => GREATEST(age, 0), months => 6))::date
+WHERE date_of_birth IS NULL;
+ALTER TABLE users ALTER COLUMN date_of_birth SET NOT NULL;
+ALTER TABLE users DROP COLUMN IF EXISTS age;
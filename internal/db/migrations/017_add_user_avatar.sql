@@ -0,0 +1,2 @@
+-- Add avatar; the key locates the resized renditions in file storage and is empty without an avatar
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_key VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,34 @@
+-- Usernames and emails are looked up in NFKC form, but accounts created before that were
+-- only trimmed and lower-cased. Bring them into the same form so that full-width and other
+-- compatibility characters match what users type. Requires a UTF-8 database (PostgreSQL 13+).
+--
+-- Refuse to migrate while accounts would collide after normalization; they have to be merged
+-- or renamed by hand first, and the error lists them
+DO $$
+DECLARE
+    conflicts TEXT;
+BEGIN
+    SELECT string_agg(kind || ' "' || value || '": ' || ids, '; ')
+    INTO conflicts
+    FROM (
+        SELECT 'username' AS kind, lower(normalize(username, NFKC)) AS value, string_agg(id::TEXT, ', ' ORDER BY created_at) AS ids
+        FROM users
+        GROUP BY lower(normalize(username, NFKC))
+        HAVING COUNT(*) > 1
+        UNION ALL
+        SELECT 'email', lower(normalize(email, NFKC)), string_agg(id::TEXT, ', ' ORDER BY created_at)
+        FROM users
+        GROUP BY lower(normalize(email, NFKC))
+        HAVING COUNT(*) > 1
+    ) duplicates;
+
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'username or email conflicts after NFKC normalization: %', conflicts;
+    END IF;
+END $$;
+
+UPDATE users SET username = normalize(username, NFKC) WHERE username <> normalize(username, NFKC);
+UPDATE users SET email = lower(normalize(email, NFKC)) WHERE email <> lower(normalize(email, NFKC));
+
+-- Usernames containing an @ are no longer accepted, since logins with an @ are looked up by
+-- email. Existing ones are kept; those users log in with their email address.
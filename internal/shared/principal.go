@@ -0,0 +1,79 @@
+package shared
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// principalKey is the context key of the request principal. It is unexported so that only
+// WithPrincipal can set the value and no other package can collide with it.
+type principalKey struct{}
+
+// Principal identifies who a request is made by. It is set by the auth module's JWT
+// middleware for every authenticated request. Requests made with a personal access token
+// have Scopes set and no SessionID.
+type Principal struct {
+	UserID    uuid.UUID
+	Username  string
+	Roles     []string
+	Scopes    []string
+	SessionID uuid.UUID
+
+	// TokenID and ExpiresAt identify the credential the request was authenticated with
+	TokenID   uuid.UUID
+	ExpiresAt time.Time
+}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal of an authenticated request
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok && principal != nil
+}
+
+// MustPrincipal returns the principal of an authenticated request. It panics when there is
+// none, so it must only be used behind the JWT middleware.
+func MustPrincipal(ctx context.Context) *Principal {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok {
+		panic("shared: no principal in context; is the route behind the JWT middleware?")
+	}
+	return principal
+}
+
+// HasRole reports whether the principal has any of the given roles
+func (p *Principal) HasRole(roles ...string) bool {
+	for _, have := range p.Roles {
+		for _, want := range roles {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsPersonalAccessToken reports whether the request was made with a personal access token
+func (p *Principal) IsPersonalAccessToken() bool {
+	return p.Scopes != nil
+}
+
+// HasScope reports whether the credential may be used for the scope. Sessions are not
+// scoped; personal access tokens must list it.
+func (p *Principal) HasScope(scope string) bool {
+	if !p.IsPersonalAccessToken() {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package shared
+
+import (
+	"musicfy/internal/config"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIP returns the IP address of the client that sent the request.
+// Proxy headers are only honoured when TRUST_PROXY_HEADERS is enabled, since clients can forge them.
+// Malformed header values fall back to the address of the peer.
+func ClientIP(r *http.Request) string {
+	server := config.AppConfig.ServerConfig
+	if server.TrustProxyHeaders {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			if ip, ok := forwardedClientIP(forwarded, server.TrustedProxyHops); ok {
+				return ip
+			}
+		} else if ip, ok := parseIP(r.Header.Get("X-Real-IP")); ok {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// forwardedClientIP picks the client IP out of X-Forwarded-For header values. Every proxy
+// appends the address it received the request from, so the entry trustedHops from the right
+// was added by the outermost trusted proxy; entries further left are set by the client.
+func forwardedClientIP(values []string, trustedHops int) (string, bool) {
+	var hops []string
+	for _, value := range values {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+	if trustedHops < 1 || len(hops) < trustedHops {
+		return "", false
+	}
+	return parseIP(hops[len(hops)-trustedHops])
+}
+
+// parseIP returns the canonical form of an IP address, or false if s is not one
+func parseIP(s string) (string, bool) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(s))
+	if err != nil {
+		return "", false
+	}
+	return addr.WithZone("").Unmap().String(), true
+}
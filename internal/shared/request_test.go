@@ -0,0 +1,59 @@
+package shared
+
+import (
+	"musicfy/internal/config"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name         string
+		trustProxy   bool
+		hops         int
+		forwardedFor []string
+		realIP       string
+		want         string
+	}{
+		{name: "peer address", want: "192.0.2.1"},
+		{name: "headers ignored when untrusted", forwardedFor: []string{"203.0.113.7"}, realIP: "203.0.113.8", want: "192.0.2.1"},
+		{name: "single hop", trustProxy: true, hops: 1, forwardedFor: []string{"203.0.113.7"}, want: "203.0.113.7"},
+		{name: "rightmost entry wins over forged ones", trustProxy: true, hops: 1, forwardedFor: []string{"10.9.9.9, 198.51.100.4, 203.0.113.7"}, want: "203.0.113.7"},
+		{name: "two trusted hops", trustProxy: true, hops: 2, forwardedFor: []string{"10.9.9.9, 203.0.113.7, 10.0.0.2"}, want: "203.0.113.7"},
+		{name: "entries across header lines", trustProxy: true, hops: 2, forwardedFor: []string{"10.9.9.9, 203.0.113.7", "10.0.0.2"}, want: "203.0.113.7"},
+		{name: "fewer entries than hops", trustProxy: true, hops: 3, forwardedFor: []string{"203.0.113.7, 10.0.0.2"}, want: "192.0.2.1"},
+		{name: "malformed entry", trustProxy: true, hops: 1, forwardedFor: []string{"203.0.113.7, not-an-ip"}, want: "192.0.2.1"},
+		{name: "overlong entry", trustProxy: true, hops: 1, forwardedFor: []string{strings.Repeat("1", 100)}, want: "192.0.2.1"},
+		{name: "empty entry", trustProxy: true, hops: 1, forwardedFor: []string{"203.0.113.7,"}, want: "192.0.2.1"},
+		{name: "real IP not used when forwarded for is malformed", trustProxy: true, hops: 1, forwardedFor: []string{"junk"}, realIP: "203.0.113.8", want: "192.0.2.1"},
+		{name: "IPv6 is canonicalised", trustProxy: true, hops: 1, forwardedFor: []string{" 2001:DB8:0:0::1 "}, want: "2001:db8::1"},
+		{name: "IPv4-mapped IPv6 is unmapped", trustProxy: true, hops: 1, forwardedFor: []string{"::ffff:203.0.113.7"}, want: "203.0.113.7"},
+		{name: "zone is dropped", trustProxy: true, hops: 1, forwardedFor: []string{"fe80::1%eth0"}, want: "fe80::1"},
+		{name: "real IP", trustProxy: true, hops: 1, realIP: "203.0.113.8", want: "203.0.113.8"},
+		{name: "malformed real IP", trustProxy: true, hops: 1, realIP: "203.0.113.8; drop", want: "192.0.2.1"},
+	}
+
+	previous := config.AppConfig.ServerConfig
+	t.Cleanup(func() { config.AppConfig.ServerConfig = previous })
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.AppConfig.ServerConfig.TrustProxyHeaders = tt.trustProxy
+			config.AppConfig.ServerConfig.TrustedProxyHops = tt.hops
+
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = "192.0.2.1:51234"
+			for _, value := range tt.forwardedFor {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			if got := ClientIP(r); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -69,7 +69,7 @@ func setupRouter() *mux.Router {
 	}
 
 	// Register auth routes
-	auth.RegisterRoutes(apiRouter)
+	auth.RegisterRoutes(router, apiRouter)
 
 	return router
 }